@@ -0,0 +1,195 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// Package actionmap maps named actions, such as "jump" or "move_x", to keys,
+// mouse buttons and gamepad inputs.
+//
+// Bindings are grouped into layers, such as "menu", "gameplay" or "text", which
+// are activated and deactivated as the application changes modes. A Config
+// holds the layers and can be saved to and loaded from JSON, so that players
+// can rebind actions. A Mapper evaluates the actions of a Config for a window
+// and a gamepad once per frame:
+//
+//	config := actionmap.NewConfig()
+//	config.Bind("gameplay", "jump", actionmap.KeyBinding(glfw.KeySpace),
+//		actionmap.GamepadButtonBinding(glfw.GamepadButtonA))
+//	config.Bind("gameplay", "move_x", actionmap.KeyAxisBinding(glfw.KeyA, -1),
+//		actionmap.KeyAxisBinding(glfw.KeyD, 1),
+//		actionmap.GamepadAxisBinding(glfw.GamepadAxisLeftX, -0.2),
+//		actionmap.GamepadAxisBinding(glfw.GamepadAxisLeftX, 0.2))
+//
+//	mapper := actionmap.NewMapper(config, win, glfw.Joystick1)
+//	mapper.SetLayers("gameplay")
+//	for !win.ShouldClose() {
+//		ctx.PollEvents()
+//		mapper.Update()
+//		if mapper.JustPressed("jump") {
+//			// Jump.
+//		}
+//		x := mapper.Axis("move_x")
+//		...
+//	}
+package actionmap
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/beta/glfw"
+)
+
+// Device is the kind of input of a binding.
+type Device string
+
+// Input devices.
+const (
+	// DeviceKey : A keyboard key. The code is a glfw.Key.
+	DeviceKey Device = "key"
+	// DeviceMouseButton : A mouse button. The code is a glfw.Button.
+	DeviceMouseButton Device = "mouse_button"
+	// DeviceGamepadButton : A gamepad button. The code is a
+	// glfw.GamepadButton.
+	DeviceGamepadButton Device = "gamepad_button"
+	// DeviceGamepadAxis : A gamepad axis. The code is a glfw.GamepadAxis.
+	DeviceGamepadAxis Device = "gamepad_axis"
+)
+
+// DefaultThreshold is the threshold of gamepad axis bindings with no
+// threshold.
+const DefaultThreshold = 0.5
+
+// Binding binds an input to an action.
+type Binding struct {
+	// Device : The kind of input.
+	Device Device `json:"device"`
+	// Code : The key, mouse button, gamepad button or gamepad axis, depending
+	// on Device.
+	Code int `json:"code"`
+	// Scale : The value the binding contributes to Mapper.Axis(). Buttons
+	// contribute Scale while held, axes contribute their value multiplied by
+	// Scale. Zero means 1.
+	Scale float32 `json:"scale,omitempty"`
+	// Threshold : For gamepad axes, the value from which the binding is
+	// considered pressed. A negative threshold is reached when the axis value
+	// is lower than or equal to it, e.g. -0.5 for left on a stick. Until the
+	// threshold is reached, the axis contributes 0 to Mapper.Axis(), so that
+	// a trigger at rest, which reports -1, does not count. Nil means
+	// DefaultThreshold.
+	Threshold *float32 `json:"threshold,omitempty"`
+}
+
+// KeyBinding returns a binding of key.
+func KeyBinding(key glfw.Key) Binding {
+	return Binding{Device: DeviceKey, Code: int(key)}
+}
+
+// KeyAxisBinding returns a binding of key contributing scale to an axis while
+// held, e.g. -1 for the key moving left on a horizontal axis.
+func KeyAxisBinding(key glfw.Key, scale float32) Binding {
+	return Binding{Device: DeviceKey, Code: int(key), Scale: scale}
+}
+
+// MouseButtonBinding returns a binding of button.
+func MouseButtonBinding(button glfw.Button) Binding {
+	return Binding{Device: DeviceMouseButton, Code: int(button)}
+}
+
+// GamepadButtonBinding returns a binding of button.
+func GamepadButtonBinding(button glfw.GamepadButton) Binding {
+	return Binding{Device: DeviceGamepadButton, Code: int(button)}
+}
+
+// GamepadAxisBinding returns a binding of axis, which is considered pressed
+// when its value reaches threshold. See Binding.Threshold.
+func GamepadAxisBinding(axis glfw.GamepadAxis, threshold float32) Binding {
+	return Binding{Device: DeviceGamepadAxis, Code: int(axis), Threshold: &threshold}
+}
+
+// scale returns the scale of b.
+func (b Binding) scale() float32 {
+	if b.Scale == 0 {
+		return 1
+	}
+	return b.Scale
+}
+
+// threshold returns the threshold of b.
+func (b Binding) threshold() float32 {
+	if b.Threshold == nil {
+		return DefaultThreshold
+	}
+	return *b.Threshold
+}
+
+// Layer is a set of action bindings that are active together, e.g. the
+// bindings of a menu or of the gameplay.
+type Layer struct {
+	// Exclusive : Whether the layer hides the layers below it while active,
+	// e.g. for text entry.
+	Exclusive bool `json:"exclusive,omitempty"`
+	// Actions : The bindings of each action.
+	Actions map[string][]Binding `json:"actions"`
+}
+
+// Config is a set of named layers of action bindings.
+type Config struct {
+	// Layers : The layers by name.
+	Layers map[string]*Layer `json:"layers"`
+}
+
+// NewConfig returns an empty Config.
+func NewConfig() *Config {
+	return &Config{Layers: make(map[string]*Layer)}
+}
+
+// ReadConfig reads a Config encoded as JSON from r.
+func ReadConfig(r io.Reader) (*Config, error) {
+	config := NewConfig()
+	if err := json.NewDecoder(r).Decode(config); err != nil {
+		return nil, err
+	}
+	if config.Layers == nil {
+		config.Layers = make(map[string]*Layer)
+	}
+	return config, nil
+}
+
+// Write writes c encoded as JSON to w.
+func (c *Config) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(c)
+}
+
+// Layer returns the layer named name, creating it if it does not exist.
+func (c *Config) Layer(name string) *Layer {
+	layer, exist := c.Layers[name]
+	if !exist {
+		layer = &Layer{Actions: make(map[string][]Binding)}
+		c.Layers[name] = layer
+	}
+	if layer.Actions == nil {
+		layer.Actions = make(map[string][]Binding)
+	}
+	return layer
+}
+
+// Bind adds bindings to action in layer.
+func (c *Config) Bind(layer, action string, bindings ...Binding) {
+	l := c.Layer(layer)
+	l.Actions[action] = append(l.Actions[action], bindings...)
+}
+
+// Rebind replaces the bindings of action in layer with bindings.
+func (c *Config) Rebind(layer, action string, bindings ...Binding) {
+	c.Layer(layer).Actions[action] = append([]Binding(nil), bindings...)
+}
+
+// Unbind removes all the bindings of action in layer.
+func (c *Config) Unbind(layer, action string) {
+	if l, exist := c.Layers[layer]; exist {
+		delete(l.Actions, action)
+	}
+}
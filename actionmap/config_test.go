@@ -0,0 +1,75 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package actionmap
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/beta/glfw"
+)
+
+func TestConfigRoundTrip(t *testing.T) {
+	config := NewConfig()
+	config.Bind("gameplay", "jump", KeyBinding(glfw.KeySpace), GamepadButtonBinding(glfw.GamepadButtonA))
+	config.Bind("gameplay", "move_x", KeyAxisBinding(glfw.KeyA, -1), KeyAxisBinding(glfw.KeyD, 1))
+	config.Bind("gameplay", "fire", MouseButtonBinding(glfw.MouseButtonLeft),
+		GamepadAxisBinding(glfw.GamepadAxisRightTrigger, 0))
+	config.Bind("gameplay", "look", Binding{Device: DeviceGamepadAxis, Code: int(glfw.GamepadAxisRightX)})
+	config.Layer("text").Exclusive = true
+	config.Bind("text", "submit", KeyBinding(glfw.KeyEnter))
+
+	var buf bytes.Buffer
+	if err := config.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadConfig(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, config) {
+		t.Errorf("ReadConfig() of the written config = %+v, want %+v", read, config)
+	}
+
+	fire := read.Layers["gameplay"].Actions["fire"][1]
+	if fire.Threshold == nil || fire.threshold() != 0 {
+		t.Errorf("threshold of 0 read as %v, want an explicit 0", fire.Threshold)
+	}
+	if look := read.Layers["gameplay"].Actions["look"][0]; look.threshold() != DefaultThreshold {
+		t.Errorf("unset threshold read as %v, want DefaultThreshold", look.threshold())
+	}
+}
+
+func TestReadConfigEmpty(t *testing.T) {
+	config, err := ReadConfig(bytes.NewBufferString("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Bind("menu", "back", KeyBinding(glfw.KeyEscape))
+	if len(config.Layers["menu"].Actions["back"]) != 1 {
+		t.Error("binding of an empty config not added")
+	}
+	if _, err := ReadConfig(bytes.NewBufferString("{")); err == nil {
+		t.Error("ReadConfig() of invalid JSON succeeded")
+	}
+}
+
+func TestConfigRebind(t *testing.T) {
+	config := NewConfig()
+	config.Bind("gameplay", "jump", KeyBinding(glfw.KeySpace))
+	config.Rebind("gameplay", "jump", KeyBinding(glfw.KeyW))
+	if bindings := config.Layers["gameplay"].Actions["jump"]; !reflect.DeepEqual(bindings, []Binding{KeyBinding(glfw.KeyW)}) {
+		t.Errorf("bindings after Rebind() = %v", bindings)
+	}
+	config.Unbind("gameplay", "jump")
+	config.Unbind("missing", "jump")
+	if _, exist := config.Layers["gameplay"].Actions["jump"]; exist {
+		t.Error("action still bound after Unbind()")
+	}
+}
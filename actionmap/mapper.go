@@ -0,0 +1,230 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package actionmap
+
+import "github.com/beta/glfw"
+
+// Mapper evaluates the actions of a Config with the input of a window and a
+// gamepad.
+//
+// Keyboard and mouse input is tracked with a glfw.InputState, so presses and
+// releases between two updates are not missed. Gamepad input is polled with
+// glfw.Joystick.GetGamepadState() on each update.
+type Mapper struct {
+	config  *Config
+	input   *glfw.InputState
+	gamepad glfw.Joystick
+	layers  []string
+
+	state      map[string]actionState
+	pad        glfw.GamepadState
+	hasPad     bool
+	lastPad    glfw.GamepadState
+	hadLastPad bool
+}
+
+// actionState is the state of an action in the current frame.
+type actionState struct {
+	down     bool
+	pressed  bool
+	released bool
+	axis     float32
+}
+
+// NewMapper returns a Mapper evaluating the actions of config with the input
+// of win and gamepad. No layer is active until set with Mapper.SetLayers() or
+// Mapper.PushLayer().
+//
+// Changes to config take effect on the next update.
+//
+// This function must only be called from the main thread.
+func NewMapper(config *Config, win *glfw.Window, gamepad glfw.Joystick) *Mapper {
+	return &Mapper{
+		config:  config,
+		input:   glfw.NewInputState(win),
+		gamepad: gamepad,
+		state:   make(map[string]actionState),
+	}
+}
+
+// Close stops tracking the input of the window.
+//
+// This function must only be called from the main thread.
+func (m *Mapper) Close() {
+	m.input.Close()
+}
+
+// SetGamepad sets the joystick whose gamepad input is mapped.
+func (m *Mapper) SetGamepad(gamepad glfw.Joystick) {
+	m.gamepad = gamepad
+}
+
+// SetLayers sets the active layers, from the bottom to the top.
+func (m *Mapper) SetLayers(layers ...string) {
+	m.layers = append(m.layers[:0], layers...)
+}
+
+// PushLayer activates layer on top of the active layers.
+func (m *Mapper) PushLayer(layer string) {
+	m.layers = append(m.layers, layer)
+}
+
+// PopLayer deactivates the top active layer and returns its name, or "" if no
+// layer is active.
+func (m *Mapper) PopLayer() string {
+	if len(m.layers) == 0 {
+		return ""
+	}
+	layer := m.layers[len(m.layers)-1]
+	m.layers = m.layers[:len(m.layers)-1]
+	return layer
+}
+
+// Layers returns the active layers, from the bottom to the top.
+func (m *Mapper) Layers() []string {
+	return append([]string(nil), m.layers...)
+}
+
+// Update evaluates the actions with the input received since the last update.
+// It is usually called once per frame, after the events have been processed.
+//
+// This function must only be called from the main thread.
+func (m *Mapper) Update() {
+	m.lastPad, m.hadLastPad = m.pad, m.hasPad
+	state, ok := m.gamepad.GetGamepadState()
+	m.hasPad = ok
+	if ok {
+		m.pad = *state
+	}
+
+	previous := m.state
+	m.state = make(map[string]actionState, len(previous))
+	for action, bindings := range m.bindings() {
+		var s actionState
+		tapped := false
+		for _, b := range bindings {
+			down, pressed, value := m.evaluate(b)
+			s.down = s.down || down
+			tapped = tapped || pressed
+			s.axis += value
+		}
+		if s.axis > 1 {
+			s.axis = 1
+		} else if s.axis < -1 {
+			s.axis = -1
+		}
+
+		wasDown := previous[action].down
+		s.pressed = !wasDown && (s.down || tapped)
+		s.released = !s.down && (wasDown || tapped)
+		m.state[action] = s
+	}
+	for action, s := range previous {
+		if _, exist := m.state[action]; !exist && s.down {
+			m.state[action] = actionState{released: true}
+		}
+	}
+
+	m.input.NextFrame()
+}
+
+// Pressed returns whether action is held down, i.e. whether any of its
+// bindings is.
+func (m *Mapper) Pressed(action string) bool {
+	return m.state[action].down
+}
+
+// JustPressed returns whether action was pressed since the last update. A
+// press and release between two updates is reported as both pressed and
+// released.
+func (m *Mapper) JustPressed(action string) bool {
+	return m.state[action].pressed
+}
+
+// JustReleased returns whether action was released since the last update.
+func (m *Mapper) JustReleased(action string) bool {
+	return m.state[action].released
+}
+
+// Axis returns the value of action, the sum of the values of its bindings
+// clamped between -1 and 1. See Binding.Scale.
+func (m *Mapper) Axis(action string) float32 {
+	return m.state[action].axis
+}
+
+// bindings returns the bindings of each action in the active layers, from the
+// top layer down to the first exclusive one.
+func (m *Mapper) bindings() map[string][]Binding {
+	bindings := make(map[string][]Binding)
+	for i := len(m.layers) - 1; i >= 0; i-- {
+		layer, exist := m.config.Layers[m.layers[i]]
+		if !exist {
+			continue
+		}
+		for action, b := range layer.Actions {
+			bindings[action] = append(bindings[action], b...)
+		}
+		if layer.Exclusive {
+			break
+		}
+	}
+	return bindings
+}
+
+// evaluate returns whether b is held down, whether it was pressed since the
+// last update and the value it contributes to the axis of its action.
+func (m *Mapper) evaluate(b Binding) (down, pressed bool, value float32) {
+	switch b.Device {
+	case DeviceKey:
+		key := glfw.Key(b.Code)
+		down, pressed = m.input.IsDown(key), m.input.JustPressed(key)
+	case DeviceMouseButton:
+		button := glfw.Button(b.Code)
+		down, pressed = m.input.IsButtonDown(button), m.input.ButtonJustPressed(button)
+	case DeviceGamepadButton, DeviceGamepadAxis:
+		// The gamepad is polled, so a press is only seen as a binding held
+		// down now but not at the last update.
+		var wasDown bool
+		down, value = evaluateGamepad(b, &m.pad, m.hasPad)
+		wasDown, _ = evaluateGamepad(b, &m.lastPad, m.hadLastPad)
+		return down, down && !wasDown, value
+	}
+	if down {
+		value = b.scale()
+	}
+	return down, pressed, value
+}
+
+// evaluateGamepad returns whether the gamepad binding b is held down in pad,
+// and the value it contributes to the axis of its action. ok is whether the
+// gamepad state is available.
+func evaluateGamepad(b Binding, pad *glfw.GamepadState, ok bool) (down bool, value float32) {
+	if !ok {
+		return false, 0
+	}
+	switch b.Device {
+	case DeviceGamepadButton:
+		if b.Code >= 0 && b.Code <= int(glfw.GamepadButtonLast) {
+			down = pad.Buttons[b.Code] == glfw.Press
+		}
+		if down {
+			value = b.scale()
+		}
+	case DeviceGamepadAxis:
+		if b.Code >= 0 && b.Code <= int(glfw.GamepadAxisLast) {
+			v := pad.Axes[b.Code]
+			if threshold := b.threshold(); threshold < 0 {
+				down = v <= threshold
+			} else {
+				down = v >= threshold
+			}
+			if down {
+				value = v * b.scale()
+			}
+		}
+	}
+	return down, value
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package actionmap
+
+import (
+	"testing"
+
+	"github.com/beta/glfw"
+)
+
+// newTestMapper initializes the library and returns a window and a mapper of
+// config for it and a synthetic gamepad. The test must call
+// Context.Terminate() when done.
+func newTestMapper(t *testing.T, config *Config) (*glfw.Context, *glfw.Window, *Mapper) {
+	ctx, err := glfw.InitErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.WindowHint(glfw.ClientAPI, glfw.NoAPI)
+	win, err := ctx.CreateWindowErr(640, 480, "test", nil, nil)
+	if err != nil {
+		ctx.Terminate()
+		t.Fatal(err)
+	}
+	glfw.Joystick1.InjectConnect("pad", "030000005e0400008e02000014010000")
+	return ctx, win, NewMapper(config, win, glfw.Joystick1)
+}
+
+func TestMapperLayers(t *testing.T) {
+	config := NewConfig()
+	config.Bind("gameplay", "jump", KeyBinding(glfw.KeySpace))
+	config.Bind("gameplay", "pause", KeyBinding(glfw.KeyEscape))
+	config.Bind("menu", "back", KeyBinding(glfw.KeyEscape))
+	config.Layer("text").Exclusive = true
+	config.Bind("text", "submit", KeyBinding(glfw.KeyEnter))
+	ctx, win, m := newTestMapper(t, config)
+	defer ctx.Terminate()
+
+	win.InjectKey(glfw.KeyEscape, 0, glfw.Press, 0)
+	m.Update()
+	if m.Pressed("pause") || m.Pressed("back") {
+		t.Error("action pressed with no active layer")
+	}
+
+	m.SetLayers("gameplay")
+	m.PushLayer("menu")
+	m.Update()
+	if !m.Pressed("pause") || !m.Pressed("back") {
+		t.Error("key not mapped to the actions of both active layers")
+	}
+
+	m.PushLayer("text")
+	win.InjectKey(glfw.KeySpace, 0, glfw.Press, 0)
+	m.Update()
+	if m.Pressed("jump") || m.Pressed("back") {
+		t.Error("exclusive layer does not hide the layers below it")
+	}
+	if !m.JustReleased("back") {
+		t.Error("action of a hidden layer held down not released")
+	}
+
+	if layer := m.PopLayer(); layer != "text" {
+		t.Errorf("PopLayer() = %q, want %q", layer, "text")
+	}
+	if layers := m.Layers(); len(layers) != 2 || layers[0] != "gameplay" || layers[1] != "menu" {
+		t.Errorf("Layers() = %v, want [gameplay menu]", layers)
+	}
+	m.Update()
+	if !m.Pressed("jump") || !m.JustPressed("jump") {
+		t.Error("action not pressed again once the exclusive layer is popped")
+	}
+	m.SetLayers()
+	if layer := m.PopLayer(); layer != "" {
+		t.Errorf("PopLayer() with no active layer = %q", layer)
+	}
+}
+
+func TestMapperKeys(t *testing.T) {
+	config := NewConfig()
+	config.Bind("gameplay", "move_x", KeyAxisBinding(glfw.KeyA, -1), KeyAxisBinding(glfw.KeyD, 1))
+	config.Bind("gameplay", "fire", MouseButtonBinding(glfw.MouseButtonLeft))
+	ctx, win, m := newTestMapper(t, config)
+	defer ctx.Terminate()
+	m.SetLayers("gameplay")
+
+	win.InjectKey(glfw.KeyD, 0, glfw.Press, 0)
+	m.Update()
+	if m.Axis("move_x") != 1 {
+		t.Errorf("Axis() with D held = %v, want 1", m.Axis("move_x"))
+	}
+	win.InjectKey(glfw.KeyA, 0, glfw.Press, 0)
+	m.Update()
+	if m.Axis("move_x") != 0 || !m.Pressed("move_x") {
+		t.Errorf("Axis() with A and D held = %v, want 0 and pressed", m.Axis("move_x"))
+	}
+
+	// A click between two updates is both pressed and released.
+	win.InjectMouseButton(glfw.MouseButtonLeft, glfw.Press, 0)
+	win.InjectMouseButton(glfw.MouseButtonLeft, glfw.Release, 0)
+	m.Update()
+	if !m.JustPressed("fire") || !m.JustReleased("fire") || m.Pressed("fire") {
+		t.Error("click between two updates not reported as pressed and released")
+	}
+}
+
+func TestMapperGamepad(t *testing.T) {
+	config := NewConfig()
+	config.Bind("gameplay", "jump", GamepadButtonBinding(glfw.GamepadButtonA))
+	config.Bind("gameplay", "fire", GamepadAxisBinding(glfw.GamepadAxisRightTrigger, 0))
+	config.Bind("gameplay", "move_x", GamepadAxisBinding(glfw.GamepadAxisLeftX, -0.2),
+		GamepadAxisBinding(glfw.GamepadAxisLeftX, 0.2))
+	ctx, _, m := newTestMapper(t, config)
+	defer ctx.Terminate()
+	m.SetLayers("gameplay")
+
+	var state glfw.GamepadState
+	state.Axes[glfw.GamepadAxisRightTrigger] = -1
+	state.Axes[glfw.GamepadAxisLeftX] = 0.1
+	glfw.Joystick1.InjectGamepadState(&state)
+	m.Update()
+	if m.Pressed("fire") || m.Axis("fire") != 0 {
+		t.Errorf("trigger at rest: pressed %v, axis %v, want released and 0", m.Pressed("fire"), m.Axis("fire"))
+	}
+	if m.Pressed("move_x") || m.Axis("move_x") != 0 {
+		t.Errorf("stick below the threshold: pressed %v, axis %v", m.Pressed("move_x"), m.Axis("move_x"))
+	}
+
+	state.Buttons[glfw.GamepadButtonA] = glfw.Press
+	state.Axes[glfw.GamepadAxisRightTrigger] = 0.5
+	state.Axes[glfw.GamepadAxisLeftX] = -0.6
+	glfw.Joystick1.InjectGamepadState(&state)
+	m.Update()
+	if !m.JustPressed("jump") || !m.Pressed("jump") {
+		t.Error("gamepad button press not reported")
+	}
+	if !m.JustPressed("fire") || m.Axis("fire") != 0.5 {
+		t.Errorf("trigger pressed: just pressed %v, axis %v, want 0.5", m.JustPressed("fire"), m.Axis("fire"))
+	}
+	if m.Axis("move_x") != -0.6 {
+		t.Errorf("Axis() of the stick pushed left = %v, want -0.6", m.Axis("move_x"))
+	}
+
+	m.Update()
+	if m.JustPressed("jump") || !m.Pressed("jump") {
+		t.Error("gamepad button held down reported as pressed again")
+	}
+
+	glfw.Joystick1.InjectDisconnect()
+	m.Update()
+	if m.Pressed("jump") || !m.JustReleased("jump") {
+		t.Error("gamepad button not released when the gamepad is disconnected")
+	}
+}
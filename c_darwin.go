@@ -18,3 +18,12 @@ package glfw
 #include "glfw/src/nsgl_context.m"
 */
 import "C"
+
+// gamepadMappingPlatform is the platform name of the gamepad mappings GLFW
+// accepts on this platform.
+const gamepadMappingPlatform = "Mac OS X"
+
+// virtualCursor reports whether the cursor position of a window is the one
+// GLFW keeps for the disabled cursor mode even when the cursor is not
+// disabled, on platforms without a system cursor.
+const virtualCursor = false
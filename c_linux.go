@@ -0,0 +1,30 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build linux,!wayland,!glfw_null
+
+package glfw
+
+/*
+#include "glfw/src/x11_init.c"
+#include "glfw/src/x11_monitor.c"
+#include "glfw/src/x11_window.c"
+#include "glfw/src/xkb_unicode.c"
+#include "glfw/src/posix_time.c"
+#include "glfw/src/posix_thread.c"
+#include "glfw/src/glx_context.c"
+#include "glfw/src/egl_context.c"
+#include "glfw/src/linux_joystick.c"
+*/
+import "C"
+
+// gamepadMappingPlatform is the platform name of the gamepad mappings GLFW
+// accepts on this platform.
+const gamepadMappingPlatform = "Linux"
+
+// virtualCursor reports whether the cursor position of a window is the one
+// GLFW keeps for the disabled cursor mode even when the cursor is not
+// disabled, on platforms without a system cursor.
+const virtualCursor = false
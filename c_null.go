@@ -0,0 +1,29 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build linux,glfw_null
+
+package glfw
+
+/*
+#include "glfw/src/null_init.c"
+#include "glfw/src/null_monitor.c"
+#include "glfw/src/null_window.c"
+#include "glfw/src/null_joystick.c"
+#include "glfw/src/posix_time.c"
+#include "glfw/src/posix_thread.c"
+*/
+import "C"
+
+// gamepadMappingPlatform is the platform name of the gamepad mappings GLFW
+// accepts on this platform. The null platform accepts mappings of any
+// platform.
+const gamepadMappingPlatform = ""
+
+// virtualCursor reports whether the cursor position of a window is the one
+// GLFW keeps for the disabled cursor mode even when the cursor is not
+// disabled. The null platform has no system cursor, so the cursor position is
+// the last position input, e.g. with Window.InjectCursorPos().
+const virtualCursor = true
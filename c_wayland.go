@@ -0,0 +1,37 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build linux,wayland,!glfw_null
+
+package glfw
+
+/*
+#include "glfw/src/wl_init.c"
+#include "glfw/src/wl_monitor.c"
+#include "glfw/src/wl_window.c"
+#include "glfw/src/xkb_unicode.c"
+#include "glfw/src/posix_time.c"
+#include "glfw/src/posix_thread.c"
+#include "glfw/src/egl_context.c"
+#include "glfw/src/linux_joystick.c"
+
+// Wayland protocol code generated from wayland-protocols.
+#include "glfw/src/wayland-xdg-shell-protocol.c"
+#include "glfw/src/wayland-xdg-decoration-protocol.c"
+#include "glfw/src/wayland-viewporter-protocol.c"
+#include "glfw/src/wayland-relative-pointer-unstable-v1-protocol.c"
+#include "glfw/src/wayland-pointer-constraints-unstable-v1-protocol.c"
+#include "glfw/src/wayland-idle-inhibit-unstable-v1-protocol.c"
+*/
+import "C"
+
+// gamepadMappingPlatform is the platform name of the gamepad mappings GLFW
+// accepts on this platform.
+const gamepadMappingPlatform = "Linux"
+
+// virtualCursor reports whether the cursor position of a window is the one
+// GLFW keeps for the disabled cursor mode even when the cursor is not
+// disabled, on platforms without a system cursor.
+const virtualCursor = false
@@ -0,0 +1,108 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package glfw
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// CallbackPanic is a panic of a callback or listener, recovered before it
+// could unwind through the C frames of GLFW.
+//
+// Callbacks are called by GLFW from C, for example from within glfwPollEvents,
+// and a panic must not unwind through C. The panic is instead recovered when
+// the callback returns to C, and raised again with a *CallbackPanic once
+// control is back in Go: Context.PollEvents(), Context.WaitEvents() and
+// Context.WaitEventsTimeout() panic with it, and Context.PollEventsErr(),
+// Context.WaitEventsErr() and Context.WaitEventsTimeoutErr() return it. The
+// event processing functions complete the frame before raising the panic: the
+// other events are delivered, the gamepads are polled and the frame is
+// recorded.
+//
+// Callbacks called outside of the event processing functions, such as the
+// error callback or the window callbacks called by Window.SetSize(), are
+// recovered the same way, and their panic is raised by the next event
+// processing function. Only the first panic is kept until it is raised; the
+// callbacks called after it still run, and their panics are discarded.
+type CallbackPanic struct {
+	// Value : The value the callback panicked with.
+	Value interface{}
+	// Stack : The stack trace of the goroutine when the callback panicked.
+	Stack []byte
+}
+
+func (p *CallbackPanic) Error() string {
+	return fmt.Sprintf("glfw: callback panicked: %v\n\n%s", p.Value, p.Stack)
+}
+
+// Unwrap returns the value of p if it is an error, or nil otherwise.
+func (p *CallbackPanic) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+var (
+	// callbackPanic is the first panic recovered from a callback, not yet
+	// raised.
+	callbackPanic *CallbackPanic
+
+	// callbackPanicMutex guards callbackPanic, as the error callback may be
+	// called from any thread.
+	callbackPanicMutex sync.Mutex
+)
+
+// recoverCallback recovers the panic of a callback, and keeps it to be raised
+// by raiseCallbackPanic(). It must be deferred by every function exported to
+// C.
+func recoverCallback() {
+	if r := recover(); r != nil {
+		p := newCallbackPanic(r)
+		callbackPanicMutex.Lock()
+		if callbackPanic == nil {
+			callbackPanic = p
+		}
+		callbackPanicMutex.Unlock()
+	}
+}
+
+// dispatchEventRecovered is like dispatchEvent, but recovers the panic of the
+// callbacks like recoverCallback(), for the events dispatched from Go by the
+// event processing functions.
+func dispatchEventRecovered(event Event) {
+	defer recoverCallback()
+	dispatchEvent(event)
+}
+
+// newCallbackPanic returns a *CallbackPanic of the recovered value r. It must
+// be called from a deferred function, so that the stack trace includes the
+// frames of the panic.
+func newCallbackPanic(r interface{}) *CallbackPanic {
+	if p, ok := r.(*CallbackPanic); ok {
+		return p
+	}
+	return &CallbackPanic{Value: r, Stack: debug.Stack()}
+}
+
+// raiseCallbackPanic panics with the panic recovered from a callback, if any.
+func raiseCallbackPanic() {
+	callbackPanicMutex.Lock()
+	p := callbackPanic
+	callbackPanic = nil
+	callbackPanicMutex.Unlock()
+	if p != nil {
+		panic(p)
+	}
+}
+
+// catchCallbackPanic recovers a panic of the event processing functions into
+// *err, as a *CallbackPanic. It must be deferred.
+func catchCallbackPanic(err *error) {
+	if r := recover(); r != nil {
+		*err = newCallbackPanic(r)
+	}
+}
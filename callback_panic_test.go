@@ -0,0 +1,99 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package glfw
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCallbackPanicCompletesFrame(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+
+	var buf bytes.Buffer
+	r, err := ctx.StartRecording(&buf, win)
+	if err != nil {
+		t.Fatal(err)
+	}
+	win.SetKeyCallback(func(win *Window, key Key, scancode int, action Action, mods ModifierFlag) {
+		panic("key")
+	})
+	var buttons []GamepadButton
+	ctx.SetGamepadButtonCallback(func(j Joystick, button GamepadButton, action Action) {
+		buttons = append(buttons, button)
+	})
+	Joystick1.InjectConnect("pad", testGUID)
+	var state GamepadState
+	state.Buttons[GamepadButtonA] = Press
+	Joystick1.InjectGamepadState(&state)
+
+	win.InjectKey(KeyA, 0, Press, 0)
+	err = ctx.PollEventsErr()
+	var p *CallbackPanic
+	if !errors.As(err, &p) || p.Value != "key" {
+		t.Fatalf("PollEventsErr() = %v, want the panic of the key callback", err)
+	}
+	if !strings.Contains(string(p.Stack), "TestCallbackPanicCompletesFrame") {
+		t.Errorf("stack of the panic does not contain the callback:\n%s", p.Stack)
+	}
+	if len(buttons) != 1 || buttons[0] != GamepadButtonA {
+		t.Errorf("gamepad button callback called with %v, want [A]", buttons)
+	}
+	if err := ctx.PollEventsErr(); err != nil {
+		t.Errorf("PollEventsErr() after the panic = %v, want nil", err)
+	}
+	if err := r.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	Joystick1.InjectDisconnect()
+
+	p2, err := ctx.StartReplay(&buf, ReplayImmediate, win)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p2.Stop()
+	var keys []Key
+	win.SetKeyCallback(func(win *Window, key Key, scancode int, action Action, mods ModifierFlag) {
+		keys = append(keys, key)
+	})
+	if err := p2.PollEvents(); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != KeyA {
+		t.Errorf("first replayed frame has keys %v, want [A]", keys)
+	}
+}
+
+func TestErrorCallbackPanic(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+
+	errBoom := errors.New("boom")
+	previous := SetErrorCallback(func(err Error, desc string) {
+		panic(errBoom)
+	})
+	defer SetErrorCallback(previous)
+
+	ctx.WindowHint(Hint(0x12345), 0)
+	ctx.WindowHint(Hint(0x12346), 0)
+	if err := ctx.PollEventsErr(); !errors.Is(err, errBoom) {
+		t.Errorf("PollEventsErr() = %v, want the panic of the error callback", err)
+	}
+
+	ctx.WindowHint(Hint(0x12345), 0)
+	defer func() {
+		if _, ok := recover().(*CallbackPanic); !ok {
+			t.Error("PollEvents() did not panic with a *CallbackPanic")
+		}
+	}()
+	ctx.PollEvents()
+}
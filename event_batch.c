@@ -0,0 +1,181 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// The C side of event batching. See Context.SetEventBatching() in
+// event_batch.go.
+
+#include "event_batch.h"
+
+int goEventBatching;
+goBatchedEvent goEventBatch[GO_EVENT_BATCH_SIZE];
+int goEventBatchLength;
+
+// _flushEventBatch is exported by event_batch.go.
+void _flushEventBatch(void);
+
+static goBatchedEvent* goAppendEvent(int type, GLFWwindow* window, uintptr_t handle) {
+	if (goEventBatchLength == GO_EVENT_BATCH_SIZE) {
+		_flushEventBatch();
+	}
+	goBatchedEvent* event = &goEventBatch[goEventBatchLength++];
+	event->type = type;
+	event->window = window;
+	event->handle = handle;
+	return event;
+}
+
+int goBatchWindowPos(GLFWwindow* window, uintptr_t handle, int x, int y) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goBatchedEvent* event = goAppendEvent(goBatchedWindowPos, window, handle);
+	event->ints[0] = x;
+	event->ints[1] = y;
+	return 1;
+}
+
+int goBatchWindowSize(GLFWwindow* window, uintptr_t handle, int width, int height) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goBatchedEvent* event = goAppendEvent(goBatchedWindowSize, window, handle);
+	event->ints[0] = width;
+	event->ints[1] = height;
+	return 1;
+}
+
+int goBatchWindowClose(GLFWwindow* window, uintptr_t handle) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goAppendEvent(goBatchedWindowClose, window, handle);
+	return 1;
+}
+
+int goBatchWindowRefresh(GLFWwindow* window, uintptr_t handle) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goAppendEvent(goBatchedWindowRefresh, window, handle);
+	return 1;
+}
+
+int goBatchWindowFocus(GLFWwindow* window, uintptr_t handle, int focused) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goBatchedEvent* event = goAppendEvent(goBatchedWindowFocus, window, handle);
+	event->ints[0] = focused;
+	return 1;
+}
+
+int goBatchWindowIconify(GLFWwindow* window, uintptr_t handle, int iconified) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goBatchedEvent* event = goAppendEvent(goBatchedWindowIconify, window, handle);
+	event->ints[0] = iconified;
+	return 1;
+}
+
+int goBatchWindowMaximize(GLFWwindow* window, uintptr_t handle, int maximized) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goBatchedEvent* event = goAppendEvent(goBatchedWindowMaximize, window, handle);
+	event->ints[0] = maximized;
+	return 1;
+}
+
+int goBatchFramebufferSize(GLFWwindow* window, uintptr_t handle, int width, int height) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goBatchedEvent* event = goAppendEvent(goBatchedFramebufferSize, window, handle);
+	event->ints[0] = width;
+	event->ints[1] = height;
+	return 1;
+}
+
+int goBatchWindowContentScale(GLFWwindow* window, uintptr_t handle, float xscale, float yscale) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goBatchedEvent* event = goAppendEvent(goBatchedWindowContentScale, window, handle);
+	event->doubles[0] = xscale;
+	event->doubles[1] = yscale;
+	return 1;
+}
+
+int goBatchKey(GLFWwindow* window, uintptr_t handle, int key, int scancode, int action, int mods) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goBatchedEvent* event = goAppendEvent(goBatchedKey, window, handle);
+	event->ints[0] = key;
+	event->ints[1] = scancode;
+	event->ints[2] = action;
+	event->ints[3] = mods;
+	return 1;
+}
+
+int goBatchChar(GLFWwindow* window, uintptr_t handle, unsigned int codepoint) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goBatchedEvent* event = goAppendEvent(goBatchedChar, window, handle);
+	event->ints[0] = codepoint;
+	return 1;
+}
+
+int goBatchCharMods(GLFWwindow* window, uintptr_t handle, unsigned int codepoint, int mods) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goBatchedEvent* event = goAppendEvent(goBatchedCharMods, window, handle);
+	event->ints[0] = codepoint;
+	event->ints[1] = mods;
+	return 1;
+}
+
+int goBatchMouseButton(GLFWwindow* window, uintptr_t handle, int button, int action, int mods) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goBatchedEvent* event = goAppendEvent(goBatchedMouseButton, window, handle);
+	event->ints[0] = button;
+	event->ints[1] = action;
+	event->ints[2] = mods;
+	return 1;
+}
+
+int goBatchCursorPos(GLFWwindow* window, uintptr_t handle, double x, double y) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goBatchedEvent* event = goAppendEvent(goBatchedCursorPos, window, handle);
+	event->doubles[0] = x;
+	event->doubles[1] = y;
+	return 1;
+}
+
+int goBatchCursorEnter(GLFWwindow* window, uintptr_t handle, int entered) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goBatchedEvent* event = goAppendEvent(goBatchedCursorEnter, window, handle);
+	event->ints[0] = entered;
+	return 1;
+}
+
+int goBatchScroll(GLFWwindow* window, uintptr_t handle, double xoffset, double yoffset) {
+	if (!goEventBatching) {
+		return 0;
+	}
+	goBatchedEvent* event = goAppendEvent(goBatchedScroll, window, handle);
+	event->doubles[0] = xoffset;
+	event->doubles[1] = yoffset;
+	return 1;
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package glfw
+
+/*
+#include "event_batch.h"
+*/
+import "C"
+import "unsafe"
+
+// SetEventBatching enables or disables event batching.
+//
+// By default, each event of a window calls into Go from C as it is processed
+// by GLFW. With event batching, the events of windows are instead appended to
+// a buffer on the C side, and delivered to the callbacks and the event queue
+// all at once when the event processing function, e.g. Context.PollEvents(),
+// returns. This saves the cost of a C to Go call per event, which adds up with
+// high polling rate mice and many windows. The buffer is also delivered when
+// it is full, and before any monitor, joystick or drop event, so that the
+// order of the events is kept.
+//
+// As the callbacks of batched events are called after GLFW processed them,
+// the state queried from a callback, e.g. with Window.GetKey(), may be more
+// recent than the event. Events generated outside of the event processing
+// functions, e.g. by Window.InjectKey(), are delivered by the next one.
+//
+// This function must only be called from the main thread.
+func (c *Context) SetEventBatching(enabled bool) {
+	if enabled {
+		C.goEventBatching = 1
+	} else {
+		flushEventBatch()
+		C.goEventBatching = 0
+	}
+}
+
+// resetEventBatch disables event batching and discards the batched events, on
+// termination.
+func resetEventBatch() {
+	C.goEventBatching = 0
+	C.goEventBatchLength = 0
+}
+
+//export _flushEventBatch
+func _flushEventBatch() {
+	defer recoverCallback()
+	flushEventBatch()
+}
+
+// flushEventBatch delivers the batched events, if any.
+//
+// The batched events are dispatched one by one like the events of unbatched
+// callbacks, rather than handed over as a []Event, so that the callbacks,
+// listeners, event queue, recorder and input states see the same events in the
+// same order whether batching is enabled or not. An application that wants
+// the events of a frame as a slice drains the event queue, which receives the
+// whole batch by the time the event processing function returns.
+//
+// A panic of a callback does not prevent the next events from being
+// delivered. It is recovered like the panics of the callbacks called from C,
+// and raised by the event processing function. See CallbackPanic.
+func flushEventBatch() {
+	if C.goEventBatchLength == 0 {
+		return
+	}
+	for _, batched := range drainEventBatch() {
+		// Skip the events of the windows destroyed by the callbacks of
+		// previous events.
+		state := windowStateOf(batched.handle)
+		if state == nil || state.win != eventWindow(batched.event) {
+			continue
+		}
+		dispatchEventRecovered(batched.event)
+	}
+}
+
+// batchedEvent is an event read from the C batch, with the handle of its
+// window.
+type batchedEvent struct {
+	handle C.uintptr_t
+	event  Event
+}
+
+// drainEventBatch returns the batched events and empties the batch, so that
+// the events generated by their callbacks are batched anew.
+func drainEventBatch() []batchedEvent {
+	length := int(C.goEventBatchLength)
+	batch := (*[C.GO_EVENT_BATCH_SIZE]C.goBatchedEvent)(unsafe.Pointer(&C.goEventBatch[0]))[:length:length]
+	events := make([]batchedEvent, 0, length)
+	for i := range batch {
+		e := &batch[i]
+		win := (*Window)(e.window)
+		a, b, c, d := int(e.ints[0]), int(e.ints[1]), int(e.ints[2]), int(e.ints[3])
+		x, y := float64(e.doubles[0]), float64(e.doubles[1])
+
+		var event Event
+		switch e._type {
+		case C.goBatchedWindowPos:
+			event = WindowPosEvent{Window: win, X: a, Y: b}
+		case C.goBatchedWindowSize:
+			event = WindowSizeEvent{Window: win, Width: a, Height: b}
+		case C.goBatchedWindowClose:
+			event = WindowCloseEvent{Window: win}
+		case C.goBatchedWindowRefresh:
+			event = WindowRefreshEvent{Window: win}
+		case C.goBatchedWindowFocus:
+			event = FocusEvent{Window: win, Focused: a == int(True)}
+		case C.goBatchedWindowIconify:
+			event = IconifyEvent{Window: win, Iconified: a == int(True)}
+		case C.goBatchedWindowMaximize:
+			event = MaximizeEvent{Window: win, Maximized: a == int(True)}
+		case C.goBatchedFramebufferSize:
+			event = FramebufferSizeEvent{Window: win, Width: a, Height: b}
+		case C.goBatchedWindowContentScale:
+			event = ContentScaleEvent{Window: win, XScale: float32(x), YScale: float32(y)}
+		case C.goBatchedKey:
+			event = KeyEvent{Window: win, Key: Key(a), Scancode: b, Action: Action(c), Mods: ModifierFlag(d)}
+		case C.goBatchedChar:
+			event = CharEvent{Window: win, Codepoint: rune(a)}
+		case C.goBatchedCharMods:
+			event = CharModsEvent{Window: win, Codepoint: rune(a), Mods: ModifierFlag(b)}
+		case C.goBatchedMouseButton:
+			event = MouseButtonEvent{Window: win, Button: Button(a), Action: Action(b), Mods: ModifierFlag(c)}
+		case C.goBatchedCursorPos:
+			event = CursorPosEvent{Window: win, X: x, Y: y}
+		case C.goBatchedCursorEnter:
+			event = CursorEnterEvent{Window: win, Entered: a == int(True)}
+		case C.goBatchedScroll:
+			event = ScrollEvent{Window: win, XOffset: x, YOffset: y}
+		default:
+			continue
+		}
+		events = append(events, batchedEvent{handle: e.handle, event: event})
+	}
+	C.goEventBatchLength = 0
+	return events
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+#ifndef GO_EVENT_BATCH_H
+#define GO_EVENT_BATCH_H
+
+#include <stdint.h>
+#include "glfw/include/GLFW/glfw3.h"
+
+// The events of windows are appended to goEventBatch while batching is
+// enabled, instead of calling their Go callbacks. When the batch is full, it
+// is flushed to Go before the next event is appended.
+
+#define GO_EVENT_BATCH_SIZE 1024
+
+enum {
+	goBatchedWindowPos,
+	goBatchedWindowSize,
+	goBatchedWindowClose,
+	goBatchedWindowRefresh,
+	goBatchedWindowFocus,
+	goBatchedWindowIconify,
+	goBatchedWindowMaximize,
+	goBatchedFramebufferSize,
+	goBatchedWindowContentScale,
+	goBatchedKey,
+	goBatchedChar,
+	goBatchedCharMods,
+	goBatchedMouseButton,
+	goBatchedCursorPos,
+	goBatchedCursorEnter,
+	goBatchedScroll
+};
+
+typedef struct {
+	int type;
+	GLFWwindow* window;
+	uintptr_t handle;
+	int ints[4];
+	double doubles[2];
+} goBatchedEvent;
+
+extern int goEventBatching;
+extern goBatchedEvent goEventBatch[GO_EVENT_BATCH_SIZE];
+extern int goEventBatchLength;
+
+int goBatchWindowPos(GLFWwindow* window, uintptr_t handle, int x, int y);
+int goBatchWindowSize(GLFWwindow* window, uintptr_t handle, int width, int height);
+int goBatchWindowClose(GLFWwindow* window, uintptr_t handle);
+int goBatchWindowRefresh(GLFWwindow* window, uintptr_t handle);
+int goBatchWindowFocus(GLFWwindow* window, uintptr_t handle, int focused);
+int goBatchWindowIconify(GLFWwindow* window, uintptr_t handle, int iconified);
+int goBatchWindowMaximize(GLFWwindow* window, uintptr_t handle, int maximized);
+int goBatchFramebufferSize(GLFWwindow* window, uintptr_t handle, int width, int height);
+int goBatchWindowContentScale(GLFWwindow* window, uintptr_t handle, float xscale, float yscale);
+int goBatchKey(GLFWwindow* window, uintptr_t handle, int key, int scancode, int action, int mods);
+int goBatchChar(GLFWwindow* window, uintptr_t handle, unsigned int codepoint);
+int goBatchCharMods(GLFWwindow* window, uintptr_t handle, unsigned int codepoint, int mods);
+int goBatchMouseButton(GLFWwindow* window, uintptr_t handle, int button, int action, int mods);
+int goBatchCursorPos(GLFWwindow* window, uintptr_t handle, double x, double y);
+int goBatchCursorEnter(GLFWwindow* window, uintptr_t handle, int entered);
+int goBatchScroll(GLFWwindow* window, uintptr_t handle, double xoffset, double yoffset);
+
+#endif
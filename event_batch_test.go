@@ -0,0 +1,113 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package glfw
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEventBatchPanic(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	queue := ctx.Events()
+
+	var keys []Key
+	win.SetKeyCallback(func(win *Window, key Key, scancode int, action Action, mods ModifierFlag) {
+		keys = append(keys, key)
+		if key == KeyA {
+			panic("key A")
+		}
+	})
+	ctx.SetEventBatching(true)
+	win.InjectKey(KeyA, 0, Press, 0)
+	win.InjectKey(KeyB, 0, Press, 0)
+	if len(keys) != 0 {
+		t.Fatalf("keys delivered before PollEvents: %v", keys)
+	}
+	if _, ok := ctx.PollEventsErr().(*CallbackPanic); !ok {
+		t.Error("PollEventsErr() did not return the panic of the key callback")
+	}
+
+	if len(keys) != 2 || keys[0] != KeyA || keys[1] != KeyB {
+		t.Errorf("key callback called with %v, want [A B]", keys)
+	}
+	if events := queue.Drain(); len(events) != 2 {
+		t.Errorf("queued events = %v, want 2 key events", events)
+	}
+}
+
+func TestEventBatchSameEvents(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	queue := ctx.Events()
+
+	inject := func(x float64) {
+		win.InjectKey(KeyA, 30, Press, ModShift)
+		win.InjectChar('A', ModShift)
+		win.InjectCursorPos(x, 2)
+		win.InjectMouseButton(MouseButtonLeft, Press, 0)
+		win.InjectScroll(0, 1)
+		win.InjectMouseButton(MouseButtonLeft, Release, 0)
+		win.InjectKey(KeyA, 30, Release, ModShift)
+	}
+	inject(1)
+	ctx.PollEvents()
+	want := queue.Drain()
+
+	ctx.SetEventBatching(true)
+	inject(1.5)
+	inject(1)
+	if events := queue.Drain(); len(events) != 0 {
+		t.Fatalf("events queued before PollEvents: %v", events)
+	}
+	ctx.PollEvents()
+	events := queue.Drain()
+	if len(events) != 2*len(want) || !reflect.DeepEqual(events[len(want):], want) {
+		t.Errorf("batched events = %v, want the unbatched events %v", events[len(want):], want)
+	}
+
+	// The batched events of a destroyed window are dropped.
+	other := createTestWindow(t, ctx)
+	other.InjectKey(KeyB, 0, Press, 0)
+	win.InjectKey(KeyC, 0, Press, 0)
+	other.Destroy()
+	ctx.PollEvents()
+	if events := queue.Drain(); len(events) != 1 || events[0].(KeyEvent).Window != win {
+		t.Errorf("batched events after a window was destroyed = %v, want only the key of the remaining window", events)
+	}
+}
+
+// benchmarkPollEvents benchmarks the delivery of cursor position events by
+// Context.PollEvents(), with or without event batching.
+func benchmarkPollEvents(b *testing.B, batching bool) {
+	ctx := initTest(b)
+	defer ctx.Terminate()
+	win := createTestWindow(b, ctx)
+	win.SetCursorPosCallback(func(win *Window, x, y float64) {})
+	ctx.SetEventBatching(batching)
+
+	const events = 64
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < events; j++ {
+			win.InjectCursorPos(float64(i), float64(j))
+		}
+		ctx.PollEvents()
+	}
+}
+
+func BenchmarkPollEvents(b *testing.B) {
+	benchmarkPollEvents(b, false)
+}
+
+func BenchmarkPollEventsBatched(b *testing.B) {
+	benchmarkPollEvents(b, true)
+}
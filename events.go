@@ -0,0 +1,527 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package glfw
+
+/*
+#include "glfw/include/GLFW/glfw3.h"
+
+void _monitorCallback(GLFWmonitor*, int);
+void _joystickCallback(int, int);
+
+static void goSetEventCallbacks() {
+	glfwSetMonitorCallback(_monitorCallback);
+	glfwSetJoystickCallback(_joystickCallback);
+}
+*/
+import "C"
+import "sync"
+
+// Event is an event delivered by an EventQueue. It is one of WindowPosEvent,
+// WindowSizeEvent, WindowCloseEvent, WindowRefreshEvent, FocusEvent,
+// IconifyEvent, MaximizeEvent, FramebufferSizeEvent, ContentScaleEvent,
+// KeyEvent, CharEvent, CharModsEvent, MouseButtonEvent, CursorPosEvent,
+// CursorEnterEvent, ScrollEvent, DropEvent, MonitorEvent, JoystickEvent,
+// GamepadButtonEvent and GamepadAxisEvent.
+//
+// Events are usually handled with a type switch:
+//
+//	switch e := event.(type) {
+//	case glfw.KeyEvent:
+//		...
+//	case glfw.MouseButtonEvent:
+//		...
+//	}
+type Event interface {
+	isEvent()
+}
+
+// WindowPosEvent is delivered when a window is moved. See WindowPosCallback.
+type WindowPosEvent struct {
+	Window *Window
+	X, Y   int
+}
+
+// WindowSizeEvent is delivered when a window is resized. See
+// WindowSizeCallback.
+type WindowSizeEvent struct {
+	Window        *Window
+	Width, Height int
+}
+
+// WindowCloseEvent is delivered when the user attempts to close a window. See
+// WindowCloseCallback.
+type WindowCloseEvent struct {
+	Window *Window
+}
+
+// WindowRefreshEvent is delivered when the content area of a window needs to
+// be redrawn. See WindowRefreshCallback.
+type WindowRefreshEvent struct {
+	Window *Window
+}
+
+// FocusEvent is delivered when a window gains or loses input focus. See
+// WindowFocusCallback.
+type FocusEvent struct {
+	Window  *Window
+	Focused bool
+}
+
+// IconifyEvent is delivered when a window is iconified or restored. See
+// WindowIconifyCallback.
+type IconifyEvent struct {
+	Window    *Window
+	Iconified bool
+}
+
+// MaximizeEvent is delivered when a window is maximized or restored. See
+// WindowMaximizeCallback.
+type MaximizeEvent struct {
+	Window    *Window
+	Maximized bool
+}
+
+// FramebufferSizeEvent is delivered when the framebuffer of a window is
+// resized. See FramebufferSizeCallback.
+type FramebufferSizeEvent struct {
+	Window        *Window
+	Width, Height int
+}
+
+// ContentScaleEvent is delivered when the content scale of a window changes.
+// See WindowContentScaleCallback.
+type ContentScaleEvent struct {
+	Window         *Window
+	XScale, YScale float32
+}
+
+// KeyEvent is delivered when a key is pressed, repeated or released. See
+// KeyCallback.
+type KeyEvent struct {
+	Window   *Window
+	Key      Key
+	Scancode int
+	Action   Action
+	Mods     ModifierFlag
+}
+
+// CharEvent is delivered when a Unicode character is input. See CharCallback.
+type CharEvent struct {
+	Window    *Window
+	Codepoint rune
+}
+
+// CharModsEvent is delivered when a Unicode character is input, regardless of
+// what modifier keys are held down. See CharModsCallback.
+type CharModsEvent struct {
+	Window    *Window
+	Codepoint rune
+	Mods      ModifierFlag
+}
+
+// MouseButtonEvent is delivered when a mouse button is pressed or released.
+// See MouseButtonCallback.
+type MouseButtonEvent struct {
+	Window *Window
+	Button Button
+	Action Action
+	Mods   ModifierFlag
+}
+
+// CursorPosEvent is delivered when the cursor is moved. See CursorPosCallback.
+type CursorPosEvent struct {
+	Window *Window
+	X, Y   float64
+}
+
+// CursorEnterEvent is delivered when the cursor enters or leaves the content
+// area of a window. See CursorEnterCallback.
+type CursorEnterEvent struct {
+	Window  *Window
+	Entered bool
+}
+
+// ScrollEvent is delivered when a scrolling device is used. See
+// ScrollCallback.
+type ScrollEvent struct {
+	Window           *Window
+	XOffset, YOffset float64
+}
+
+// DropEvent is delivered when paths are dropped on a window. See DropCallback.
+type DropEvent struct {
+	Window *Window
+	Paths  []string
+}
+
+// MonitorEvent is delivered when a monitor is connected or disconnected. See
+// MonitorCallback.
+type MonitorEvent struct {
+	Monitor *Monitor
+	Event   ConnectionEvent
+}
+
+// JoystickEvent is delivered when a joystick is connected or disconnected. See
+// JoystickCallback.
+type JoystickEvent struct {
+	Joystick Joystick
+	Event    ConnectionEvent
+}
+
+// GamepadButtonEvent is delivered when a gamepad button is pressed or
+// released. See GamepadButtonCallback.
+type GamepadButtonEvent struct {
+	Joystick Joystick
+	Button   GamepadButton
+	Action   Action
+}
+
+// GamepadAxisEvent is delivered when a gamepad axis moves. See
+// GamepadAxisCallback.
+type GamepadAxisEvent struct {
+	Joystick Joystick
+	Axis     GamepadAxis
+	Value    float32
+}
+
+func (WindowPosEvent) isEvent()       {}
+func (WindowSizeEvent) isEvent()      {}
+func (WindowCloseEvent) isEvent()     {}
+func (WindowRefreshEvent) isEvent()   {}
+func (FocusEvent) isEvent()           {}
+func (IconifyEvent) isEvent()         {}
+func (MaximizeEvent) isEvent()        {}
+func (FramebufferSizeEvent) isEvent() {}
+func (ContentScaleEvent) isEvent()    {}
+func (KeyEvent) isEvent()             {}
+func (CharEvent) isEvent()            {}
+func (CharModsEvent) isEvent()        {}
+func (MouseButtonEvent) isEvent()     {}
+func (CursorPosEvent) isEvent()       {}
+func (CursorEnterEvent) isEvent()     {}
+func (ScrollEvent) isEvent()          {}
+func (DropEvent) isEvent()            {}
+func (MonitorEvent) isEvent()         {}
+func (JoystickEvent) isEvent()        {}
+func (GamepadButtonEvent) isEvent()   {}
+func (GamepadAxisEvent) isEvent()     {}
+
+// EventQueue is a queue of the events of all windows, monitors and joysticks.
+//
+// Events are queued on the main thread by the event processing functions, e.g.
+// Context.PollEvents(), after any callback set for them has been called. The
+// queue may be drained from any goroutine.
+//
+// The queue holds at most DefaultEventQueueLimit events unless
+// EventQueue.SetLimit() is called. The events that occur while the queue is
+// full are dropped and counted by EventQueue.Dropped().
+type EventQueue struct {
+	mu      sync.Mutex
+	events  []Event
+	limit   int
+	dropped int
+	closed  bool
+	ready   chan struct{}
+}
+
+// DefaultEventQueueLimit is the default maximum number of events held by an
+// EventQueue, enough for a few seconds of the events of a 1000 Hz mouse.
+const DefaultEventQueueLimit = 4096
+
+// eventQueue is the queue returned by Context.Events(), or nil if events are
+// not enabled.
+var eventQueue *EventQueue
+
+// Events enables the event queue and returns it. Events are queued until the
+// queue is closed or the library is terminated.
+//
+// The event queue works alongside callbacks. Callbacks set for windows,
+// monitors and joysticks are still called, before their events are queued.
+//
+// Returns the same queue for every call until the queue is closed or the
+// library is terminated.
+//
+// Possible errors include NotInitialized.
+//
+// This function must only be called from the main thread.
+func (c *Context) Events() *EventQueue {
+	if eventQueue == nil {
+		enableEvents()
+		eventQueue = &EventQueue{limit: DefaultEventQueueLimit, ready: make(chan struct{}, 1)}
+	}
+	return eventQueue
+}
+
+// Drain returns all the queued events in the order they occurred, and clears
+// the queue. Returns nil if no event is queued.
+//
+// This function may be called from any goroutine.
+func (q *EventQueue) Drain() []Event {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	events := q.events
+	q.events = nil
+	return events
+}
+
+// Ready returns a channel that receives a value when events are queued after
+// the channel was last received from. It allows a goroutine to wait for events
+// and drain the queue:
+//
+//	for range queue.Ready() {
+//		for _, event := range queue.Drain() {
+//			...
+//		}
+//	}
+//
+// The channel is closed by EventQueue.Close(), which ends the loop.
+//
+// This function may be called from any goroutine.
+func (q *EventQueue) Ready() <-chan struct{} {
+	return q.ready
+}
+
+// SetLimit sets the maximum number of events held by the queue to limit, or
+// removes the limit if limit is zero or negative. The events already queued
+// are kept even if they exceed the new limit.
+//
+// This function may be called from any goroutine.
+func (q *EventQueue) SetLimit(limit int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.limit = limit
+}
+
+// Dropped returns the number of events dropped because the queue was full.
+//
+// This function may be called from any goroutine.
+func (q *EventQueue) Dropped() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+// Close disables the event queue: no more events are queued, and the channel
+// returned by EventQueue.Ready() is closed. The events queued before can still
+// be drained. The next call to Context.Events() returns a new queue.
+//
+// Closing a closed queue does nothing.
+//
+// This function must only be called from the main thread.
+func (q *EventQueue) Close() {
+	if eventQueue == q {
+		eventQueue = nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.closed {
+		q.closed = true
+		close(q.ready)
+	}
+}
+
+// push appends event to the queue, unless it is full, and signals the ready
+// channel.
+func (q *EventQueue) push(event Event) {
+	q.mu.Lock()
+	if q.limit > 0 && len(q.events) >= q.limit {
+		q.dropped++
+		q.mu.Unlock()
+		return
+	}
+	q.events = append(q.events, event)
+	q.mu.Unlock()
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// pushEvent queues event if events are enabled, records it if recording and
+// not replaying, and updates the input states with it.
+func pushEvent(event Event) {
+	if eventQueue != nil {
+		eventQueue.push(event)
+	}
+	if recorder != nil && !replaying {
+		recorder.record(event)
+	}
+	for _, s := range inputStates {
+		s.handle(event)
+	}
+}
+
+// eventsEnabled reports whether events are enabled, recorded, tracked by
+// input states or listened to, i.e. whether GLFW callbacks must stay set even
+// when no Go callback is set.
+func eventsEnabled() bool {
+	return eventQueue != nil || recorder != nil || len(inputStates) > 0 || eventListeners
+}
+
+// enableEvents sets all the GLFW callbacks, unless they are already set.
+func enableEvents() {
+	if eventsEnabled() {
+		return
+	}
+	C.goSetEventCallbacks()
+	for _, state := range loadWindowStates() {
+		if state != nil {
+			enableWindowEvents(state.win)
+		}
+	}
+}
+
+// dispatchEvent calls the callback set for event, and queues and records it
+// like the events dispatched by GLFW.
+func dispatchEvent(event Event) {
+	dispatchEventTo(eventWindow(event).state(), event)
+}
+
+// dispatchEventTo is like dispatchEvent, with state the state of the window of
+// event, or nil if event is not a window event or its window has no state.
+//
+// The listeners subscribed to event are notified first, and the callback set
+// for event is called unless a listener stopped the propagation. event is
+// queued and recorded even if a listener or the callback panics.
+func dispatchEventTo(state *windowState, event Event) {
+	defer pushEvent(event)
+	if notifyListeners(state, event) {
+		return
+	}
+
+	callbacks := new(WindowCallbacks)
+	if state != nil {
+		callbacks = &state.callbacks
+	}
+	switch e := event.(type) {
+	case WindowPosEvent:
+		if callbacks.PosCallback != nil {
+			callbacks.PosCallback(e.Window, e.X, e.Y)
+		}
+	case WindowSizeEvent:
+		if callbacks.SizeCallback != nil {
+			callbacks.SizeCallback(e.Window, e.Width, e.Height)
+		}
+	case WindowCloseEvent:
+		if callbacks.CloseCallback != nil {
+			callbacks.CloseCallback(e.Window)
+		}
+	case WindowRefreshEvent:
+		if callbacks.RefreshCallback != nil {
+			callbacks.RefreshCallback(e.Window)
+		}
+	case FocusEvent:
+		if callbacks.FocusCallback != nil {
+			callbacks.FocusCallback(e.Window, e.Focused)
+		}
+	case IconifyEvent:
+		if callbacks.IconifyCallback != nil {
+			callbacks.IconifyCallback(e.Window, e.Iconified)
+		}
+	case MaximizeEvent:
+		if callbacks.MaximizeCallback != nil {
+			callbacks.MaximizeCallback(e.Window, e.Maximized)
+		}
+	case FramebufferSizeEvent:
+		if callbacks.FramebufferSizeCallback != nil {
+			callbacks.FramebufferSizeCallback(e.Window, e.Width, e.Height)
+		}
+	case ContentScaleEvent:
+		if callbacks.ContentScaleCallback != nil {
+			callbacks.ContentScaleCallback(e.Window, e.XScale, e.YScale)
+		}
+	case KeyEvent:
+		if callbacks.KeyCallback != nil {
+			callbacks.KeyCallback(e.Window, e.Key, e.Scancode, e.Action, e.Mods)
+		}
+	case CharEvent:
+		if callbacks.CharCallback != nil {
+			callbacks.CharCallback(e.Window, e.Codepoint)
+		}
+	case CharModsEvent:
+		if callbacks.CharModsCallback != nil {
+			callbacks.CharModsCallback(e.Window, e.Codepoint, e.Mods)
+		}
+	case MouseButtonEvent:
+		if callbacks.MouseButtonCallback != nil {
+			callbacks.MouseButtonCallback(e.Window, e.Button, e.Action, e.Mods)
+		}
+	case CursorPosEvent:
+		if callbacks.CursorPosCallback != nil {
+			callbacks.CursorPosCallback(e.Window, e.X, e.Y)
+		}
+	case CursorEnterEvent:
+		if callbacks.CursorEnterCallback != nil {
+			callbacks.CursorEnterCallback(e.Window, e.Entered)
+		}
+	case ScrollEvent:
+		if callbacks.ScrollCallback != nil {
+			callbacks.ScrollCallback(e.Window, e.XOffset, e.YOffset)
+		}
+	case DropEvent:
+		if callbacks.DropCallback != nil {
+			callbacks.DropCallback(e.Window, e.Paths)
+		}
+	case MonitorEvent:
+		if monitorCallback != nil {
+			monitorCallback(e.Monitor, e.Event)
+		}
+	case JoystickEvent:
+		if joystickCallback != nil {
+			joystickCallback(e.Joystick, e.Event)
+		}
+	case GamepadButtonEvent:
+		if gamepadButtonCallback != nil {
+			gamepadButtonCallback(e.Joystick, e.Button, e.Action)
+		}
+	case GamepadAxisEvent:
+		if gamepadAxisCallback != nil {
+			gamepadAxisCallback(e.Joystick, e.Axis, e.Value)
+		}
+	}
+}
+
+// eventWindow returns the window of event, or nil if it is not a window event.
+func eventWindow(event Event) *Window {
+	switch e := event.(type) {
+	case WindowPosEvent:
+		return e.Window
+	case WindowSizeEvent:
+		return e.Window
+	case WindowCloseEvent:
+		return e.Window
+	case WindowRefreshEvent:
+		return e.Window
+	case FocusEvent:
+		return e.Window
+	case IconifyEvent:
+		return e.Window
+	case MaximizeEvent:
+		return e.Window
+	case FramebufferSizeEvent:
+		return e.Window
+	case ContentScaleEvent:
+		return e.Window
+	case KeyEvent:
+		return e.Window
+	case CharEvent:
+		return e.Window
+	case CharModsEvent:
+		return e.Window
+	case MouseButtonEvent:
+		return e.Window
+	case CursorPosEvent:
+		return e.Window
+	case CursorEnterEvent:
+		return e.Window
+	case ScrollEvent:
+		return e.Window
+	case DropEvent:
+		return e.Window
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package glfw
+
+import "testing"
+
+func TestEventQueueLimit(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	queue := ctx.Events()
+
+	queue.SetLimit(3)
+	for i := 1; i <= 5; i++ {
+		win.InjectCursorPos(float64(i), 0)
+	}
+	events := queue.Drain()
+	if len(events) != 3 {
+		t.Fatalf("queue with a limit of 3 held %d events", len(events))
+	}
+	if e, ok := events[2].(CursorPosEvent); !ok || e.X != 3 {
+		t.Errorf("last queued event = %v, want the third cursor position", events[2])
+	}
+	if queue.Dropped() != 2 {
+		t.Errorf("Dropped() = %d, want 2", queue.Dropped())
+	}
+
+	queue.SetLimit(0)
+	for i := 1; i <= DefaultEventQueueLimit+1; i++ {
+		win.InjectCursorPos(float64(i), 0)
+	}
+	if events := queue.Drain(); len(events) != DefaultEventQueueLimit+1 {
+		t.Errorf("queue with no limit held %d events, want %d", len(events), DefaultEventQueueLimit+1)
+	}
+	if queue.Dropped() != 2 {
+		t.Errorf("Dropped() with no limit = %d, want 2", queue.Dropped())
+	}
+}
+
+func TestEventQueueClose(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	queue := ctx.Events()
+
+	win.InjectCursorPos(1, 0)
+	queue.Close()
+	queue.Close()
+	win.InjectCursorPos(2, 0)
+	if events := queue.Drain(); len(events) != 1 {
+		t.Errorf("closed queue held %v, want the event queued before Close()", events)
+	}
+	for range queue.Ready() {
+	}
+
+	reopened := ctx.Events()
+	if reopened == queue {
+		t.Fatal("Events() after Close() returned the closed queue")
+	}
+	win.InjectCursorPos(3, 0)
+	if events := reopened.Drain(); len(events) != 1 {
+		t.Errorf("new queue held %v, want one event", events)
+	}
+	if events := queue.Drain(); events != nil {
+		t.Errorf("event queued on the closed queue: %v", events)
+	}
+
+	<-reopened.Ready()
+	ctx.Terminate()
+	select {
+	case _, ok := <-reopened.Ready():
+		if ok {
+			t.Error("queue signaled ready by Terminate()")
+		}
+	default:
+		t.Error("Terminate() did not close the queue")
+	}
+	initTest(t) // for the deferred Terminate()
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package glfw
+
+//go:generate go run gen_gamecontrollerdb.go -o gamecontrollerdb.go -rev "GLFW 3.3.0" glfw/src/mappings.h
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// GamepadMappingPlatform is the value of the platform field of the gamepad
+// mappings GLFW accepts in this build, e.g. "Windows", "Mac OS X" or "Linux".
+// It is empty on the null platform, which accepts mappings of any platform.
+const GamepadMappingPlatform = gamepadMappingPlatform
+
+// Limits of gamepad mappings imposed by GLFW.
+const (
+	maxGamepadMappingLength     = 1023
+	maxGamepadMappingNameLength = 127
+)
+
+// GamepadMappingError describes a gamepad mapping rejected by
+// Context.LoadGamepadMappings().
+type GamepadMappingError struct {
+	// Line : The line number of the mapping, starting at 1.
+	Line int
+	// Mapping : The rejected mapping.
+	Mapping string
+	// Reason : Why the mapping was rejected.
+	Reason string
+}
+
+func (err *GamepadMappingError) Error() string {
+	return fmt.Sprintf("glfw: gamepad mapping on line %d rejected: %s", err.Line, err.Reason)
+}
+
+// GamepadMappingErrors lists the gamepad mappings rejected by
+// Context.LoadGamepadMappings(), in the order of their lines.
+type GamepadMappingErrors []*GamepadMappingError
+
+func (errs GamepadMappingErrors) Error() string {
+	if len(errs) == 1 {
+		return errs[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more)", errs[0].Error(), len(errs)-1)
+}
+
+// FilterGamepadMappings returns the mappings of platform among mappings, one
+// per line, and the mappings with no platform field. Comments, empty lines and
+// mappings of other platforms are removed.
+func FilterGamepadMappings(mappings, platform string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(mappings, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if p, ok := gamepadMappingPlatformOf(line); ok && p != platform {
+			continue
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// LoadGamepadMappings reads gamepad mappings in the SDL_GameControllerDB
+// format from r and adds them to the internal list, like
+// Context.UpdateGamepadMappings().
+//
+// Each line is validated before being passed to GLFW. Empty lines and comments
+// starting with # are ignored, as are the mappings whose platform field names
+// another platform than GamepadMappingPlatform. The other lines that GLFW would
+// reject or misinterpret are skipped and returned as GamepadMappingErrors,
+// while the valid mappings are still added.
+//
+// Possible errors include NotInitialized and InvalidValue.
+//
+// This function must only be called from the main thread.
+func (c *Context) LoadGamepadMappings(r io.Reader) error {
+	var accepted strings.Builder
+	var rejected GamepadMappingErrors
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if p, ok := gamepadMappingPlatformOf(line); ok && gamepadMappingPlatform != "" && p != gamepadMappingPlatform {
+			continue
+		}
+		if reason := checkGamepadMapping(line); reason != "" {
+			rejected = append(rejected, &GamepadMappingError{Line: lineNumber, Mapping: line, Reason: reason})
+			continue
+		}
+		accepted.WriteString(line)
+		accepted.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if accepted.Len() > 0 {
+		if err := c.UpdateGamepadMappingsErr(accepted.String()); err != nil {
+			return err
+		}
+	}
+	if len(rejected) > 0 {
+		return rejected
+	}
+	return nil
+}
+
+// LoadGamepadMappingsFile loads the gamepad mappings of the file named name,
+// e.g. a gamecontrollerdb.txt file. See Context.LoadGamepadMappings().
+//
+// This function must only be called from the main thread.
+func (c *Context) LoadGamepadMappingsFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.LoadGamepadMappings(f)
+}
+
+// LoadDefaultGamepadMappings loads the mappings of GameControllerDB for this
+// platform. See Context.LoadGamepadMappings().
+//
+// GameControllerDB holds the mappings bundled with the vendored GLFW, which
+// GLFW already loads on initialization. This function is useful once
+// GameControllerDB is regenerated from a newer revision of the community
+// database, see gen_gamecontrollerdb.go.
+//
+// This function must only be called from the main thread.
+func (c *Context) LoadDefaultGamepadMappings() error {
+	return c.LoadGamepadMappings(strings.NewReader(GameControllerDB))
+}
+
+// LoadGamepadMappingsFromEnv loads the gamepad mappings set by the user in the
+// environment, like SDL does: the mappings of the SDL_GAMECONTROLLERCONFIG
+// variable, one per line, and those of the file named by the
+// SDL_GAMECONTROLLERCONFIG_FILE variable. Unset variables are ignored. See
+// Context.LoadGamepadMappings().
+//
+// This function must only be called from the main thread.
+func (c *Context) LoadGamepadMappingsFromEnv() error {
+	if name := os.Getenv("SDL_GAMECONTROLLERCONFIG_FILE"); name != "" {
+		if err := c.LoadGamepadMappingsFile(name); err != nil {
+			return err
+		}
+	}
+	if mappings := os.Getenv("SDL_GAMECONTROLLERCONFIG"); mappings != "" {
+		return c.LoadGamepadMappings(strings.NewReader(mappings))
+	}
+	return nil
+}
+
+// gamepadMappingPlatformOf returns the value of the platform field of mapping.
+// ok is false if mapping has no platform field.
+func gamepadMappingPlatformOf(mapping string) (platform string, ok bool) {
+	for _, field := range strings.Split(mapping, ",") {
+		if strings.HasPrefix(field, "platform:") {
+			return strings.TrimPrefix(field, "platform:"), true
+		}
+	}
+	return "", false
+}
+
+// checkGamepadMapping returns why GLFW would reject or misinterpret mapping, or
+// "" if it is valid.
+func checkGamepadMapping(mapping string) string {
+	if len(mapping) > maxGamepadMappingLength {
+		return fmt.Sprintf("mapping is longer than %d bytes", maxGamepadMappingLength)
+	}
+	_, reason := parseGamepadMapping(mapping)
+	return reason
+}
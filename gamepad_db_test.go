@@ -0,0 +1,87 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package glfw
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoadDefaultGamepadMappings(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+
+	if err := ctx.LoadDefaultGamepadMappings(); err != nil {
+		t.Errorf("LoadDefaultGamepadMappings() = %v", err)
+	}
+	if err := ctx.LoadGamepadMappings(strings.NewReader(GameControllerDB)); err != nil {
+		t.Errorf("LoadGamepadMappings(GameControllerDB) = %v", err)
+	}
+}
+
+func TestLoadGamepadMappingsErrors(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+
+	mappings := strings.Join([]string{
+		"# Comment",
+		gamepadMappingLine,
+		"",
+		"0123,Short GUID,a:b0,",
+		"03000000000000000000000000000001,Bad Button,a:x0,",
+		"03000000000000000000000000000002," + strings.Repeat("n", 2000) + ",a:b0,",
+		"03000000000000000000000000000003,Other Pad,a:b0,platform:Windows,",
+	}, "\n")
+	err := ctx.LoadGamepadMappings(strings.NewReader(mappings))
+	var errs GamepadMappingErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("LoadGamepadMappings() = %v, want GamepadMappingErrors", err)
+	}
+	if len(errs) != 3 {
+		t.Fatalf("LoadGamepadMappings() rejected %d lines, want 3: %v", len(errs), errs)
+	}
+	for i, line := range []int{4, 5, 6} {
+		if errs[i].Line != line || errs[i].Reason == "" {
+			t.Errorf("error %d = line %d, reason %q, want line %d with a reason", i, errs[i].Line, errs[i].Reason, line)
+		}
+	}
+	if errs[0].Mapping != "0123,Short GUID,a:b0," {
+		t.Errorf("rejected mapping = %q", errs[0].Mapping)
+	}
+	if !strings.Contains(err.Error(), "line 4") || !strings.Contains(err.Error(), "2 more") {
+		t.Errorf("Error() = %q, want the first line and the count of the others", err.Error())
+	}
+}
+
+func TestFilterGamepadMappings(t *testing.T) {
+	mappings := strings.Join([]string{
+		"# Windows",
+		"03000000000000000000000000000001,Windows Pad,a:b0,platform:Windows,",
+		"",
+		"  03000000000000000000000000000002,Linux Pad,a:b0,platform:Linux,  ",
+		"03000000000000000000000000000003,Any Pad,a:b0,",
+	}, "\n")
+
+	linux := FilterGamepadMappings(mappings, "Linux")
+	want := "03000000000000000000000000000002,Linux Pad,a:b0,platform:Linux,\n" +
+		"03000000000000000000000000000003,Any Pad,a:b0,\n"
+	if linux != want {
+		t.Errorf("FilterGamepadMappings(Linux) = %q, want %q", linux, want)
+	}
+	if other := FilterGamepadMappings(mappings, "Mac OS X"); other != "03000000000000000000000000000003,Any Pad,a:b0,\n" {
+		t.Errorf("FilterGamepadMappings(Mac OS X) = %q", other)
+	}
+	for _, platform := range []string{"Windows", "Mac OS X", "Linux"} {
+		for _, line := range strings.Split(strings.TrimSpace(FilterGamepadMappings(GameControllerDB, platform)), "\n") {
+			if p, ok := gamepadMappingPlatformOf(line); ok && p != platform {
+				t.Fatalf("FilterGamepadMappings(GameControllerDB, %s) kept %q", platform, line)
+			}
+		}
+	}
+}
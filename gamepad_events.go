@@ -0,0 +1,151 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package glfw
+
+// GamepadButtonCallback is the function type for gamepad button callbacks.
+//
+// j is the joystick whose gamepad button was pressed or released. button is
+// the gamepad button. action is one of Press or Release.
+type GamepadButtonCallback func(j Joystick, button GamepadButton, action Action)
+
+// GamepadAxisCallback is the function type for gamepad axis callbacks.
+//
+// j is the joystick whose gamepad axis moved. axis is the gamepad axis. value
+// is the new value of the axis, in the range -1.0 to 1.0 inclusive.
+type GamepadAxisCallback func(j Joystick, axis GamepadAxis, value float32)
+
+// DefaultGamepadAxisEpsilon is the default minimum change of a gamepad axis
+// reported by a GamepadAxisEvent. See Context.SetGamepadAxisEpsilon().
+const DefaultGamepadAxisEpsilon = 0.01
+
+var (
+	gamepadButtonCallback GamepadButtonCallback
+	gamepadAxisCallback   GamepadAxisCallback
+	gamepadAxisEpsilon    float32 = DefaultGamepadAxisEpsilon
+
+	// reportedGamepadStates are the gamepad states as last reported by
+	// gamepad events. Disconnected joysticks and joysticks with no gamepad
+	// mapping have a zero state.
+	reportedGamepadStates [JoystickLast + 1]GamepadState
+)
+
+// SetGamepadButtonCallback sets the gamepad button callback, or removes the
+// currently set callback. This is called when a button of a gamepad is
+// pressed or released.
+//
+// GLFW has no gamepad callbacks. Instead, while a gamepad callback or listener
+// is set or the event queue is enabled, the event processing functions poll the gamepad
+// state of every connected joystick with a gamepad mapping, and report the
+// changes since the previous call. Presses and releases between two calls are
+// therefore missed. A joystick that is disconnected or loses its mapping is
+// reported as having all its buttons released and its axes at 0.
+//
+// callback is the new callback, or nil to remove the currently set callback.
+//
+// Returns the previously set callback, or nil if no callback was set or the
+// library had not been initialized.
+//
+// This function must only be called from the main thread.
+func (c *Context) SetGamepadButtonCallback(callback GamepadButtonCallback) GamepadButtonCallback {
+	previousCallback := gamepadButtonCallback
+	gamepadButtonCallback = callback
+	return previousCallback
+}
+
+// SetGamepadAxisCallback sets the gamepad axis callback, or removes the
+// currently set callback. This is called when an axis of a gamepad moves by
+// at least the epsilon set with Context.SetGamepadAxisEpsilon(). See
+// Context.SetGamepadButtonCallback() for how gamepads are polled.
+//
+// callback is the new callback, or nil to remove the currently set callback.
+//
+// Returns the previously set callback, or nil if no callback was set or the
+// library had not been initialized.
+//
+// This function must only be called from the main thread.
+func (c *Context) SetGamepadAxisCallback(callback GamepadAxisCallback) GamepadAxisCallback {
+	previousCallback := gamepadAxisCallback
+	gamepadAxisCallback = callback
+	return previousCallback
+}
+
+// SetGamepadAxisEpsilon sets the minimum change of a gamepad axis since it was
+// last reported for a new GamepadAxisEvent to be delivered. Smaller changes are
+// ignored to filter out the noise of analog sticks, except when the axis
+// reaches -1, 0 or 1, which is always reported. An epsilon of 0 reports every
+// change. The default is DefaultGamepadAxisEpsilon.
+//
+// This function must only be called from the main thread.
+func (c *Context) SetGamepadAxisEpsilon(epsilon float32) {
+	if epsilon < 0 {
+		epsilon = 0
+	}
+	gamepadAxisEpsilon = epsilon
+}
+
+// gamepadEventsEnabled reports whether the gamepads must be polled for gamepad
+// events by the event processing functions.
+func gamepadEventsEnabled() bool {
+	return gamepadButtonCallback != nil || gamepadAxisCallback != nil || eventQueue != nil ||
+		!gamepadButtonListeners.empty() || !gamepadAxisListeners.empty()
+}
+
+// pollGamepadEvents polls the gamepad state of the joysticks and delivers the
+// gamepad events of the changes since the last poll. The panics of the
+// callbacks are recovered, and raised by the event processing function.
+func pollGamepadEvents() {
+	var zero GamepadState
+	for j := Joystick1; j <= JoystickLast; j++ {
+		reported := &reportedGamepadStates[j]
+		if !gamepadPolled(j) && *reported == zero {
+			continue
+		}
+		state := zero
+		if s, ok := j.GetGamepadState(); ok {
+			state = *s
+		}
+
+		for button, action := range state.Buttons {
+			if action != reported.Buttons[button] {
+				reported.Buttons[button] = action
+				dispatchEventRecovered(GamepadButtonEvent{Joystick: j, Button: GamepadButton(button), Action: action})
+			}
+		}
+		for axis, value := range state.Axes {
+			if gamepadAxisMoved(reported.Axes[axis], value) {
+				reported.Axes[axis] = value
+				dispatchEventRecovered(GamepadAxisEvent{Joystick: j, Axis: GamepadAxis(axis), Value: value})
+			}
+		}
+	}
+}
+
+// gamepadPolled reports whether the gamepad state of j is worth polling, i.e.
+// whether j is present or has a replayed gamepad state.
+func gamepadPolled(j Joystick) bool {
+	if replayer != nil {
+		if _, found := replayer.gamepads[j]; found {
+			return true
+		}
+	}
+	return j.Present()
+}
+
+// gamepadAxisMoved reports whether an axis moved enough from the reported
+// value to the current value to be reported again.
+func gamepadAxisMoved(reported, value float32) bool {
+	if value == reported {
+		return false
+	}
+	if value == -1 || value == 0 || value == 1 {
+		return true
+	}
+	delta := value - reported
+	if delta < 0 {
+		delta = -delta
+	}
+	return delta >= gamepadAxisEpsilon
+}
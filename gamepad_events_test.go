@@ -0,0 +1,141 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package glfw
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGamepadCallbacks(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+
+	var events []Event
+	ctx.SetGamepadButtonCallback(func(j Joystick, button GamepadButton, action Action) {
+		events = append(events, GamepadButtonEvent{Joystick: j, Button: button, Action: action})
+	})
+	ctx.SetGamepadAxisCallback(func(j Joystick, axis GamepadAxis, value float32) {
+		events = append(events, GamepadAxisEvent{Joystick: j, Axis: axis, Value: value})
+	})
+	poll := func(want ...Event) {
+		t.Helper()
+		events = nil
+		ctx.PollEvents()
+		if !reflect.DeepEqual(events, want) {
+			t.Errorf("gamepad callbacks called with %v, want %v", events, want)
+		}
+	}
+
+	Joystick2.InjectConnect("Test Pad", testGUID)
+	poll()
+
+	var state GamepadState
+	state.Buttons[GamepadButtonA] = Press
+	state.Axes[GamepadAxisLeftX] = 0.5
+	Joystick2.InjectGamepadState(&state)
+	poll(
+		GamepadButtonEvent{Joystick: Joystick2, Button: GamepadButtonA, Action: Press},
+		GamepadAxisEvent{Joystick: Joystick2, Axis: GamepadAxisLeftX, Value: 0.5},
+	)
+	poll()
+
+	// Changes below the epsilon are ignored, except to -1, 0 or 1.
+	state.Axes[GamepadAxisLeftX] = 0.505
+	state.Axes[GamepadAxisRightY] = -0.005
+	Joystick2.InjectGamepadState(&state)
+	poll()
+	state.Axes[GamepadAxisLeftX] = 0.52
+	state.Axes[GamepadAxisRightY] = 0
+	state.Axes[GamepadAxisRightX] = 1
+	Joystick2.InjectGamepadState(&state)
+	poll(
+		GamepadAxisEvent{Joystick: Joystick2, Axis: GamepadAxisLeftX, Value: 0.52},
+		GamepadAxisEvent{Joystick: Joystick2, Axis: GamepadAxisRightX, Value: 1},
+	)
+	ctx.SetGamepadAxisEpsilon(0)
+	state.Axes[GamepadAxisLeftX] = 0.521
+	Joystick2.InjectGamepadState(&state)
+	poll(GamepadAxisEvent{Joystick: Joystick2, Axis: GamepadAxisLeftX, Value: 0.521})
+
+	// A press and release between two polls is missed.
+	state.Buttons[GamepadButtonB] = Press
+	Joystick2.InjectGamepadState(&state)
+	state.Buttons[GamepadButtonB] = Release
+	Joystick2.InjectGamepadState(&state)
+	poll()
+
+	// A disconnected gamepad is reported as released and centered.
+	Joystick2.InjectDisconnect()
+	poll(
+		GamepadButtonEvent{Joystick: Joystick2, Button: GamepadButtonA, Action: Release},
+		GamepadAxisEvent{Joystick: Joystick2, Axis: GamepadAxisLeftX, Value: 0},
+		GamepadAxisEvent{Joystick: Joystick2, Axis: GamepadAxisRightX, Value: 0},
+	)
+	poll()
+}
+
+func TestGamepadEventsQueue(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	Joystick1.InjectConnect("Test Pad", testGUID)
+	defer Joystick1.InjectDisconnect()
+
+	// With no callback, listener or queue, the gamepads are not polled.
+	var state GamepadState
+	state.Buttons[GamepadButtonStart] = Press
+	Joystick1.InjectGamepadState(&state)
+	ctx.PollEvents()
+	if reportedGamepadStates[Joystick1] != (GamepadState{}) {
+		t.Fatal("gamepad polled with no callback, listener or queue")
+	}
+
+	queue := ctx.Events()
+	state.Axes[GamepadAxisLeftTrigger] = -1
+	Joystick1.InjectGamepadState(&state)
+	ctx.PollEvents()
+	want := []Event{
+		GamepadButtonEvent{Joystick: Joystick1, Button: GamepadButtonStart, Action: Press},
+		GamepadAxisEvent{Joystick: Joystick1, Axis: GamepadAxisLeftTrigger, Value: -1},
+	}
+	if events := queue.Drain(); !reflect.DeepEqual(events, want) {
+		t.Errorf("queued gamepad events = %v, want %v", events, want)
+	}
+}
+
+func TestGamepadAxisEpsilon(t *testing.T) {
+	ctx := initTest(t)
+
+	ctx.SetGamepadAxisEpsilon(-1)
+	if gamepadAxisEpsilon != 0 {
+		t.Errorf("negative epsilon set as %v, want 0", gamepadAxisEpsilon)
+	}
+	ctx.SetGamepadAxisEpsilon(0.1)
+	tests := []struct {
+		reported, value float32
+		want            bool
+	}{
+		{0.5, 0.5, false},
+		{0.5, 0.55, false},
+		{0.5, 0.65, true},
+		{0.5, 0.35, true},
+		{0.05, 0, true},
+		{0.95, 1, true},
+		{-0.95, -1, true},
+	}
+	for _, test := range tests {
+		if got := gamepadAxisMoved(test.reported, test.value); got != test.want {
+			t.Errorf("gamepadAxisMoved(%v, %v) = %v, want %v", test.reported, test.value, got, test.want)
+		}
+	}
+
+	ctx.Terminate()
+	if gamepadAxisEpsilon != DefaultGamepadAxisEpsilon {
+		t.Errorf("epsilon after Terminate() = %v, want the default", gamepadAxisEpsilon)
+	}
+}
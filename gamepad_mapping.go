@@ -0,0 +1,481 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package glfw
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JoystickInputKind is the kind of a joystick input.
+type JoystickInputKind int
+
+// Joystick input kinds.
+const (
+	// JoystickAxis : A joystick axis, as returned by Joystick.GetAxes().
+	JoystickAxis JoystickInputKind = iota + 1
+	// JoystickButton : A joystick button, as returned by Joystick.GetButtons().
+	JoystickButton
+	// JoystickHat : A direction of a joystick hat, as returned by
+	// Joystick.GetHats().
+	JoystickHat
+)
+
+// JoystickInput is a joystick input that a gamepad mapping binds to a gamepad
+// button or axis.
+type JoystickInput struct {
+	// Kind : The kind of the input.
+	Kind JoystickInputKind
+	// Index : The index of the axis, button or hat.
+	Index int
+	// Hat : For hats, the direction of the hat, e.g. HatUp. The input is
+	// pressed while any of the directions combined in Hat is.
+	Hat HatState
+	// Half : For axes, 1 to use only the positive half of the axis, -1 to use
+	// only the negative half, or 0 to use the full axis.
+	Half int
+	// Invert : For axes, whether the axis is inverted.
+	Invert bool
+}
+
+// AxisInput returns the input of the full axis index.
+func AxisInput(index int) JoystickInput {
+	return JoystickInput{Kind: JoystickAxis, Index: index}
+}
+
+// HalfAxisInput returns the input of the positive or negative half of the
+// axis index.
+func HalfAxisInput(index int, positive bool) JoystickInput {
+	half := -1
+	if positive {
+		half = 1
+	}
+	return JoystickInput{Kind: JoystickAxis, Index: index, Half: half}
+}
+
+// InvertedAxisInput returns the input of the full axis index, inverted.
+func InvertedAxisInput(index int) JoystickInput {
+	return JoystickInput{Kind: JoystickAxis, Index: index, Invert: true}
+}
+
+// ButtonInput returns the input of the button index.
+func ButtonInput(index int) JoystickInput {
+	return JoystickInput{Kind: JoystickButton, Index: index}
+}
+
+// HatInput returns the input of the direction hat, e.g. HatUp, of the hat
+// index.
+func HatInput(index int, hat HatState) JoystickInput {
+	return JoystickInput{Kind: JoystickHat, Index: index, Hat: hat}
+}
+
+// String returns in in the mapping format, e.g. "a0", "+a2", "a1~", "b3" or
+// "h0.4".
+func (in JoystickInput) String() string {
+	switch in.Kind {
+	case JoystickAxis:
+		var prefix, suffix string
+		if in.Half > 0 {
+			prefix = "+"
+		} else if in.Half < 0 {
+			prefix = "-"
+		}
+		if in.Invert {
+			suffix = "~"
+		}
+		return prefix + "a" + strconv.Itoa(in.Index) + suffix
+	case JoystickButton:
+		return "b" + strconv.Itoa(in.Index)
+	case JoystickHat:
+		return "h" + strconv.Itoa(in.Index) + "." + strconv.Itoa(int(in.Hat))
+	}
+	return ""
+}
+
+// gamepadButtonFields are the mapping field names of the gamepad buttons.
+var gamepadButtonFields = [GamepadButtonLast + 1]string{
+	GamepadButtonA:           "a",
+	GamepadButtonB:           "b",
+	GamepadButtonX:           "x",
+	GamepadButtonY:           "y",
+	GamepadButtonLeftBumper:  "leftshoulder",
+	GamepadButtonRightBumper: "rightshoulder",
+	GamepadButtonBack:        "back",
+	GamepadButtonStart:       "start",
+	GamepadButtonGuide:       "guide",
+	GamepadButtonLeftThumb:   "leftstick",
+	GamepadButtonRightThumb:  "rightstick",
+	GamepadButtonDPadUp:      "dpup",
+	GamepadButtonDPadRight:   "dpright",
+	GamepadButtonDPadDown:    "dpdown",
+	GamepadButtonDPadLeft:    "dpleft",
+}
+
+// gamepadAxisFields are the mapping field names of the gamepad axes.
+var gamepadAxisFields = [GamepadAxisLast + 1]string{
+	GamepadAxisLeftX:        "leftx",
+	GamepadAxisLeftY:        "lefty",
+	GamepadAxisRightX:       "rightx",
+	GamepadAxisRightY:       "righty",
+	GamepadAxisLeftTrigger:  "lefttrigger",
+	GamepadAxisRightTrigger: "righttrigger",
+}
+
+// GamepadMapping is a gamepad mapping in the SDL_GameControllerDB format, as
+// passed to Context.UpdateGamepadMappings(). It binds the inputs of the
+// joysticks with a GUID to gamepad buttons and axes:
+//
+//	030000005e0400008e02000010010000,Xbox 360 Controller,a:b0,leftx:a0,dpup:h0.1,platform:Linux,
+//
+// A mapping can be parsed from a string with ParseGamepadMapping(), or built
+// with NewGamepadMapping() and the Set methods:
+//
+//	mapping := glfw.NewGamepadMapping(guid, "My Controller").
+//		SetButton(glfw.GamepadButtonA, glfw.ButtonInput(0)).
+//		SetAxis(glfw.GamepadAxisLeftX, glfw.AxisInput(0))
+//	ctx.UpdateGamepadMappings(mapping.String())
+type GamepadMapping struct {
+	// GUID : The GUID of the joysticks the mapping applies to, as returned by
+	// Joystick.GetGUID().
+	GUID string
+	// Name : The human-readable name of the gamepad.
+	Name string
+	// Platform : The platform the mapping applies to, e.g. "Windows", or "" for
+	// all platforms.
+	Platform string
+	// Buttons : The inputs bound to gamepad buttons.
+	Buttons map[GamepadButton]JoystickInput
+	// Axes : The inputs bound to gamepad axes.
+	Axes map[GamepadAxis]JoystickInput
+	// Extra : The fields GLFW ignores, such as "crc:a1b2", kept as is.
+	Extra []string
+}
+
+// NewGamepadMapping returns a mapping with no bindings for the joysticks with
+// guid.
+func NewGamepadMapping(guid, name string) *GamepadMapping {
+	return &GamepadMapping{
+		GUID:    guid,
+		Name:    name,
+		Buttons: make(map[GamepadButton]JoystickInput),
+		Axes:    make(map[GamepadAxis]JoystickInput),
+	}
+}
+
+// SetButton binds in to button and returns m.
+func (m *GamepadMapping) SetButton(button GamepadButton, in JoystickInput) *GamepadMapping {
+	m.Buttons[button] = in
+	return m
+}
+
+// SetAxis binds in to axis and returns m.
+func (m *GamepadMapping) SetAxis(axis GamepadAxis, in JoystickInput) *GamepadMapping {
+	m.Axes[axis] = in
+	return m
+}
+
+// SetPlatform sets the platform of m and returns m.
+func (m *GamepadMapping) SetPlatform(platform string) *GamepadMapping {
+	m.Platform = platform
+	return m
+}
+
+// ParseGamepadMapping parses a mapping in the SDL_GameControllerDB format.
+//
+// Output modifiers, e.g. "+leftx:a0", are rejected as GLFW does not support
+// them.
+func ParseGamepadMapping(s string) (*GamepadMapping, error) {
+	m, reason := parseGamepadMapping(strings.TrimSpace(s))
+	if reason != "" {
+		return nil, fmt.Errorf("glfw: invalid gamepad mapping: %s", reason)
+	}
+	return m, nil
+}
+
+// parseGamepadMapping parses s, and returns why it is invalid if it is.
+func parseGamepadMapping(s string) (*GamepadMapping, string) {
+	fields := strings.Split(s, ",")
+	if len(fields) < 2 {
+		return nil, "missing name"
+	}
+	m := NewGamepadMapping(fields[0], fields[1])
+	if reason := checkGUID(m.GUID); reason != "" {
+		return nil, reason
+	}
+	if len(fields) == 2 {
+		// GLFW requires the name to be terminated by a comma.
+		return nil, "missing comma after name"
+	}
+
+fields:
+	for _, field := range fields[2:] {
+		if field == "" {
+			continue
+		}
+		if field[0] == '+' || field[0] == '-' {
+			return nil, fmt.Sprintf("output modifier in %q is not supported", field)
+		}
+		i := strings.IndexByte(field, ':')
+		if i < 0 {
+			m.Extra = append(m.Extra, field)
+			continue
+		}
+		name, value := field[:i], field[i+1:]
+		if name == "platform" {
+			m.Platform = value
+			continue
+		}
+		for button, buttonName := range gamepadButtonFields {
+			if name == buttonName {
+				in, reason := parseJoystickInput(value)
+				if reason != "" {
+					return nil, name + ": " + reason
+				}
+				m.Buttons[GamepadButton(button)] = in
+				continue fields
+			}
+		}
+		for axis, axisName := range gamepadAxisFields {
+			if name == axisName {
+				in, reason := parseJoystickInput(value)
+				if reason != "" {
+					return nil, name + ": " + reason
+				}
+				m.Axes[GamepadAxis(axis)] = in
+				continue fields
+			}
+		}
+		// GLFW ignores unknown fields.
+		m.Extra = append(m.Extra, field)
+	}
+
+	if reason := m.check(); reason != "" {
+		return nil, reason
+	}
+	return m, ""
+}
+
+// parseJoystickInput parses the input of a mapping field, e.g. "b0", "+a2",
+// "a1~" or "h0.4", and returns why it is invalid if it is.
+func parseJoystickInput(s string) (JoystickInput, string) {
+	var in JoystickInput
+	input := s
+	invalid := fmt.Sprintf("invalid input %q", input)
+
+	if strings.HasPrefix(s, "+") {
+		in.Half, s = 1, s[1:]
+	} else if strings.HasPrefix(s, "-") {
+		in.Half, s = -1, s[1:]
+	}
+	if s == "" {
+		return in, "missing input"
+	}
+
+	kind, s := s[0], s[1:]
+	switch kind {
+	case 'a':
+		in.Kind = JoystickAxis
+		if strings.HasSuffix(s, "~") {
+			in.Invert, s = true, s[:len(s)-1]
+		}
+	case 'b':
+		in.Kind = JoystickButton
+	case 'h':
+		in.Kind = JoystickHat
+		i := strings.IndexByte(s, '.')
+		if i < 0 {
+			return in, invalid
+		}
+		hat, err := strconv.ParseUint(s[i+1:], 10, 8)
+		if err != nil {
+			return in, invalid
+		}
+		in.Hat, s = HatState(hat), s[:i]
+	default:
+		return in, fmt.Sprintf("invalid input %q, must be an axis, button or hat", input)
+	}
+	if in.Kind != JoystickAxis && (in.Half != 0 || in.Invert) {
+		return in, fmt.Sprintf("invalid input %q, only axes can have modifiers", input)
+	}
+
+	index, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return in, invalid
+	}
+	in.Index = int(index)
+	return in, ""
+}
+
+// String returns m in the canonical mapping format: the GUID in lower case,
+// the name, the button and axis fields sorted by name, the extra fields, the
+// platform field if any, and a trailing comma.
+func (m *GamepadMapping) String() string {
+	var fields []string
+	for button, in := range m.Buttons {
+		if button >= 0 && button <= GamepadButtonLast {
+			fields = append(fields, gamepadButtonFields[button]+":"+in.String())
+		}
+	}
+	for axis, in := range m.Axes {
+		if axis >= 0 && axis <= GamepadAxisLast {
+			fields = append(fields, gamepadAxisFields[axis]+":"+in.String())
+		}
+	}
+	sort.Strings(fields)
+
+	var b strings.Builder
+	b.WriteString(strings.ToLower(m.GUID))
+	b.WriteByte(',')
+	b.WriteString(m.Name)
+	b.WriteByte(',')
+	for _, field := range append(fields, m.Extra...) {
+		b.WriteString(field)
+		b.WriteByte(',')
+	}
+	if m.Platform != "" {
+		b.WriteString("platform:")
+		b.WriteString(m.Platform)
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// Validate returns an error if GLFW would reject or misinterpret m.
+func (m *GamepadMapping) Validate() error {
+	if reason := m.check(); reason != "" {
+		return fmt.Errorf("glfw: invalid gamepad mapping: %s", reason)
+	}
+	return nil
+}
+
+// ValidateFor returns an error if m is invalid or does not apply to j: if the
+// GUID of m is not that of j, or if m binds an axis, button or hat that j does
+// not have.
+//
+// The buttons of j include the buttons emulating its hats if the
+// JoystickHatButtons init hint is set, which is the default.
+//
+// This function must only be called from the main thread.
+func (m *GamepadMapping) ValidateFor(j Joystick) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	if !j.Present() {
+		return errors.New("glfw: joystick is not present")
+	}
+	if guid := j.GetGUID(); !strings.EqualFold(m.GUID, guid) {
+		return fmt.Errorf("glfw: gamepad mapping GUID %s does not match joystick GUID %s", m.GUID, guid)
+	}
+
+	axes, buttons, hats := len(j.GetAxes()), len(j.GetButtons()), len(j.GetHats())
+	check := func(field string, in JoystickInput) error {
+		var count int
+		switch in.Kind {
+		case JoystickAxis:
+			count = axes
+		case JoystickButton:
+			count = buttons
+		case JoystickHat:
+			count = hats
+		}
+		if in.Index >= count {
+			return fmt.Errorf("glfw: gamepad mapping %s:%s is out of range, the joystick has %d axes, %d buttons and %d hats",
+				field, in, axes, buttons, hats)
+		}
+		return nil
+	}
+	for button, in := range m.Buttons {
+		if err := check(gamepadButtonFields[button], in); err != nil {
+			return err
+		}
+	}
+	for axis, in := range m.Axes {
+		if err := check(gamepadAxisFields[axis], in); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check returns why GLFW would reject or misinterpret m, or "" if it is valid.
+func (m *GamepadMapping) check() string {
+	if reason := checkGUID(m.GUID); reason != "" {
+		return reason
+	}
+	if m.Name == "" {
+		return "missing name"
+	}
+	if strings.ContainsAny(m.Name, ",\r\n") {
+		return fmt.Sprintf("name %q contains a comma or a newline", m.Name)
+	}
+	if len(m.Name) > maxGamepadMappingNameLength {
+		return fmt.Sprintf("name is longer than %d bytes", maxGamepadMappingNameLength)
+	}
+
+	for button, in := range m.Buttons {
+		if button < 0 || button > GamepadButtonLast {
+			return fmt.Sprintf("invalid gamepad button %d", button)
+		}
+		if reason := in.check(); reason != "" {
+			return gamepadButtonFields[button] + ": " + reason
+		}
+	}
+	for axis, in := range m.Axes {
+		if axis < 0 || axis > GamepadAxisLast {
+			return fmt.Sprintf("invalid gamepad axis %d", axis)
+		}
+		if reason := in.check(); reason != "" {
+			return gamepadAxisFields[axis] + ": " + reason
+		}
+	}
+	for _, field := range m.Extra {
+		if field == "" || strings.ContainsAny(field, ",\r\n") || field[0] == '+' || field[0] == '-' {
+			return fmt.Sprintf("invalid extra field %q", field)
+		}
+	}
+
+	if n := len(m.String()); n > maxGamepadMappingLength {
+		return fmt.Sprintf("mapping is %d bytes long, longer than %d bytes", n, maxGamepadMappingLength)
+	}
+	return ""
+}
+
+// check returns why in is invalid, or "" if it is valid.
+func (in JoystickInput) check() string {
+	if in.Index < 0 || in.Index > 255 {
+		return fmt.Sprintf("index %d of %s is out of range", in.Index, in)
+	}
+	switch in.Kind {
+	case JoystickAxis:
+		return ""
+	case JoystickButton:
+	case JoystickHat:
+		if in.Hat == 0 || in.Hat&^(HatUp|HatRight|HatDown|HatLeft) != 0 {
+			return fmt.Sprintf("invalid hat direction in %s", in)
+		}
+		if in.Index > 15 {
+			return fmt.Sprintf("hat index of %s is out of range", in)
+		}
+	default:
+		return "invalid input kind"
+	}
+	if in.Half != 0 || in.Invert {
+		return fmt.Sprintf("modifiers on %s, only axes can have modifiers", in)
+	}
+	return ""
+}
+
+// checkGUID returns why guid is not a valid joystick GUID, or "" if it is
+// valid.
+func checkGUID(guid string) string {
+	if len(guid) != 32 || strings.Trim(guid, "0123456789abcdefABCDEF") != "" {
+		return fmt.Sprintf("GUID %q is not 32 hexadecimal digits", guid)
+	}
+	return ""
+}
@@ -0,0 +1,436 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package glfw
+
+import (
+	"math"
+	"strings"
+)
+
+// DeadzoneShape is the shape of the deadzone of a stick.
+type DeadzoneShape int
+
+// Deadzone shapes.
+const (
+	// DeadzoneScaledRadial : The stick is ignored while its distance from the
+	// center is below the deadzone, and the remaining distance is rescaled to
+	// start from 0. This is the default, and the best fit for most games.
+	DeadzoneScaledRadial DeadzoneShape = iota
+	// DeadzoneRadial : The stick is ignored while its distance from the center
+	// is below the deadzone, with no rescaling, so the values jump from 0 to
+	// the deadzone.
+	DeadzoneRadial
+	// DeadzoneAxial : Each axis of the stick is processed on its own, which
+	// snaps the stick to the axes near the center. This suits movement on a
+	// grid.
+	DeadzoneAxial
+)
+
+// AxisProfile describes how to process the value of an axis or a trigger.
+//
+// The absolute value of the axis is ignored below Deadzone, rescaled from
+// Deadzone–Saturation to 0–1, and raised to the power of Exponent.
+type AxisProfile struct {
+	// Deadzone : The value below which the axis is considered at rest, from 0
+	// to 1.
+	Deadzone float32
+	// Saturation : The value from which the axis is considered fully pushed,
+	// to compensate for worn or imprecise hardware. Zero means 1.
+	Saturation float32
+	// Exponent : The exponent of the response curve. Values above 1 give more
+	// precision near the center. Zero means 1, a linear response.
+	Exponent float32
+}
+
+// Process returns value, in the range -1.0 to 1.0, processed with p.
+func (p *AxisProfile) Process(value float32) float32 {
+	if value < 0 {
+		return -p.process(-value)
+	}
+	return p.process(value)
+}
+
+// process processes the absolute value of an axis.
+func (p *AxisProfile) process(value float32) float32 {
+	return curve(rescale(value, p.Deadzone, p.Saturation), p.Exponent)
+}
+
+// StickProfile describes how to process the position of a stick.
+type StickProfile struct {
+	// Shape : The shape of the deadzone.
+	Shape DeadzoneShape
+	// Deadzone : The distance from the center, or the value of each axis with
+	// DeadzoneAxial, below which the stick is considered at rest, from 0 to 1.
+	Deadzone float32
+	// Saturation : The distance from the center from which the stick is
+	// considered fully pushed. Zero means 1.
+	Saturation float32
+	// Exponent : The exponent of the response curve, applied to the distance
+	// from the center. Zero means 1, a linear response.
+	Exponent float32
+}
+
+// Process returns the position x, y of a stick processed with p. The length of
+// the returned vector is at most 1.
+func (p *StickProfile) Process(x, y float32) StickVector {
+	if p.Shape == DeadzoneAxial {
+		axis := AxisProfile{Deadzone: p.Deadzone, Saturation: p.Saturation, Exponent: p.Exponent}
+		return StickVector{axis.Process(x), axis.Process(y)}.clamp()
+	}
+
+	length := StickVector{x, y}.Length()
+	if length == 0 || length < p.Deadzone {
+		return StickVector{}
+	}
+	var scaled float32
+	if p.Shape == DeadzoneRadial {
+		scaled = rescale(length, 0, p.Saturation)
+	} else {
+		scaled = rescale(length, p.Deadzone, p.Saturation)
+	}
+	scaled = curve(scaled, p.Exponent)
+	return StickVector{x / length * scaled, y / length * scaled}
+}
+
+// StickVector is the position of a stick. Like the gamepad axes, X increases
+// to the right and Y increases downwards.
+type StickVector struct {
+	X, Y float32
+}
+
+// Length returns the distance of v from the center.
+func (v StickVector) Length() float32 {
+	return float32(math.Hypot(float64(v.X), float64(v.Y)))
+}
+
+// Direction returns the direction v points to, as one of the 8 directions of a
+// hat, or HatCentered if v is at the center.
+func (v StickVector) Direction() HatState {
+	if v.X == 0 && v.Y == 0 {
+		return HatCentered
+	}
+	// Split the circle into 8 sectors of 45°, starting from the right.
+	sector := int(math.Floor(v.angle()/(math.Pi/4)+0.5)+8) % 8
+	return stickDirections[sector]
+}
+
+// stickDirections are the directions of the sectors of StickVector.Direction(),
+// counterclockwise from the right.
+var stickDirections = [8]HatState{
+	HatRight, HatRightUp, HatUp, HatLeftUp,
+	HatLeft, HatLeftDown, HatDown, HatRightDown,
+}
+
+// angle returns the angle of v counterclockwise from the right, in radians.
+func (v StickVector) angle() float64 {
+	return math.Atan2(float64(-v.Y), float64(v.X))
+}
+
+// clamp returns v shortened to a length of 1 if it is longer.
+func (v StickVector) clamp() StickVector {
+	if length := v.Length(); length > 1 {
+		return StickVector{v.X / length, v.Y / length}
+	}
+	return v
+}
+
+// Hysteresis converts an analog value to a digital state, with different
+// thresholds for pressing and releasing so that a value hovering around a
+// threshold does not toggle the state on each frame.
+type Hysteresis struct {
+	// Press : The value from which the state becomes pressed.
+	Press float32
+	// Release : The value below which the state becomes released. Zero means
+	// 0.8 times Press, and a value above Press is clamped to it.
+	Release float32
+
+	down bool
+}
+
+// Update updates the state with value and returns whether it is pressed.
+func (h *Hysteresis) Update(value float32) bool {
+	if h.down {
+		h.down = value >= h.release()
+	} else {
+		h.down = value >= h.Press
+	}
+	return h.down
+}
+
+// Down returns whether the state is pressed.
+func (h *Hysteresis) Down() bool {
+	return h.down
+}
+
+// release returns the effective release threshold of h.
+func (h *Hysteresis) release() float32 {
+	switch {
+	case h.Release == 0:
+		return h.Press * 0.8
+	case h.Release > h.Press:
+		return h.Press
+	}
+	return h.Release
+}
+
+// GamepadProfile describes how to process the state of a gamepad and the raw
+// axes of a joystick.
+type GamepadProfile struct {
+	// LeftStick : The processing of the left stick.
+	LeftStick StickProfile
+	// RightStick : The processing of the right stick.
+	RightStick StickProfile
+	// LeftTrigger : The processing of the left trigger, once mapped to the
+	// range 0.0 to 1.0.
+	LeftTrigger AxisProfile
+	// RightTrigger : The processing of the right trigger, once mapped to the
+	// range 0.0 to 1.0.
+	RightTrigger AxisProfile
+	// Axes : The processing of the raw axes of the joystick, as returned by
+	// Joystick.GetAxes().
+	Axes AxisProfile
+
+	// StickPress : The distance from the center from which a stick is
+	// considered pushed in a direction. Zero means 0.5.
+	StickPress float32
+	// StickRelease : The distance from the center below which a stick is no
+	// longer considered pushed. Zero means 0.8 times StickPress.
+	StickRelease float32
+	// TriggerPress : The value from which a trigger is considered pressed.
+	// Zero means 0.5.
+	TriggerPress float32
+	// TriggerRelease : The value below which a trigger is no longer considered
+	// pressed. Zero means 0.8 times TriggerPress.
+	TriggerRelease float32
+}
+
+// DefaultGamepadProfile is the profile of the gamepads with no profile of
+// their own in a new GamepadProfiles.
+var DefaultGamepadProfile = GamepadProfile{
+	LeftStick:    StickProfile{Shape: DeadzoneScaledRadial, Deadzone: 0.15},
+	RightStick:   StickProfile{Shape: DeadzoneScaledRadial, Deadzone: 0.15},
+	LeftTrigger:  AxisProfile{Deadzone: 0.05},
+	RightTrigger: AxisProfile{Deadzone: 0.05},
+	Axes:         AxisProfile{Deadzone: 0.15},
+}
+
+// Process returns state processed with p. The digital states of the sticks and
+// triggers are computed with no hysteresis; use a GamepadProcessor to track
+// them across frames.
+func (p *GamepadProfile) Process(state *GamepadState) ProcessedGamepadState {
+	processed := p.process(state)
+	processed.LeftStickDirection = p.stickDirection(processed.LeftStick, HatCentered)
+	processed.RightStickDirection = p.stickDirection(processed.RightStick, HatCentered)
+	processed.LeftTriggerDown = processed.LeftTrigger >= orDefault(p.TriggerPress, 0.5)
+	processed.RightTriggerDown = processed.RightTrigger >= orDefault(p.TriggerPress, 0.5)
+	return processed
+}
+
+// process processes the analog values of state.
+func (p *GamepadProfile) process(state *GamepadState) ProcessedGamepadState {
+	return ProcessedGamepadState{
+		Buttons:      state.Buttons,
+		LeftStick:    p.LeftStick.Process(state.Axes[GamepadAxisLeftX], state.Axes[GamepadAxisLeftY]),
+		RightStick:   p.RightStick.Process(state.Axes[GamepadAxisRightX], state.Axes[GamepadAxisRightY]),
+		LeftTrigger:  p.LeftTrigger.process((state.Axes[GamepadAxisLeftTrigger] + 1) / 2),
+		RightTrigger: p.RightTrigger.process((state.Axes[GamepadAxisRightTrigger] + 1) / 2),
+	}
+}
+
+// stickDirectionHysteresis is the angle, in radians, by which a stick must
+// move past the edge of the sector of its direction to change direction, so
+// that a stick pushed between two directions does not flicker between them.
+const stickDirectionHysteresis = math.Pi / 18
+
+// stickDirection returns the direction of a stick at v, which was pushed in
+// the direction previous in the previous frame.
+func (p *GamepadProfile) stickDirection(v StickVector, previous HatState) HatState {
+	h := Hysteresis{Press: orDefault(p.StickPress, 0.5), Release: p.StickRelease, down: previous != HatCentered}
+	if !h.Update(v.Length()) {
+		return HatCentered
+	}
+	for i, direction := range stickDirections {
+		if direction != previous {
+			continue
+		}
+		// Keep the previous direction until the stick is past the edge of its
+		// sector by the hysteresis angle.
+		offset := math.Abs(math.Remainder(v.angle()-float64(i)*math.Pi/4, 2*math.Pi))
+		if offset <= math.Pi/8+stickDirectionHysteresis {
+			return previous
+		}
+	}
+	return v.Direction()
+}
+
+// ProcessAxes returns the raw axes of a joystick processed with p.Axes.
+func (p *GamepadProfile) ProcessAxes(axes []float32) []float32 {
+	processed := make([]float32, len(axes))
+	for i, value := range axes {
+		processed[i] = p.Axes.Process(value)
+	}
+	return processed
+}
+
+// ProcessedGamepadState is the state of a gamepad processed with a
+// GamepadProfile.
+type ProcessedGamepadState struct {
+	// Buttons : The states of each gamepad button, Press or Release.
+	Buttons [15]Action
+	// LeftStick : The position of the left stick.
+	LeftStick StickVector
+	// RightStick : The position of the right stick.
+	RightStick StickVector
+	// LeftTrigger : The value of the left trigger, in the range 0.0 to 1.0
+	// inclusive.
+	LeftTrigger float32
+	// RightTrigger : The value of the right trigger, in the range 0.0 to 1.0
+	// inclusive.
+	RightTrigger float32
+
+	// LeftStickDirection : The direction the left stick is pushed in, or
+	// HatCentered.
+	LeftStickDirection HatState
+	// RightStickDirection : The direction the right stick is pushed in, or
+	// HatCentered.
+	RightStickDirection HatState
+	// LeftTriggerDown : Whether the left trigger is pressed.
+	LeftTriggerDown bool
+	// RightTriggerDown : Whether the right trigger is pressed.
+	RightTriggerDown bool
+}
+
+// GamepadProfiles holds the profiles of gamepads, keyed by the GUID returned
+// by Joystick.GetGUID(), so that each model of gamepad can be tuned.
+//
+// A GamepadProfiles is not safe for concurrent use. Like the GamepadProcessors
+// using it, it must only be used from the main thread.
+type GamepadProfiles struct {
+	// Default : The profile of the gamepads with no profile of their own.
+	Default GamepadProfile
+
+	profiles map[string]GamepadProfile
+}
+
+// NewGamepadProfiles returns a GamepadProfiles with DefaultGamepadProfile as
+// the default profile.
+func NewGamepadProfiles() *GamepadProfiles {
+	return &GamepadProfiles{
+		Default:  DefaultGamepadProfile,
+		profiles: make(map[string]GamepadProfile),
+	}
+}
+
+// Set sets the profile of the gamepads with guid.
+func (p *GamepadProfiles) Set(guid string, profile GamepadProfile) {
+	if p.profiles == nil {
+		p.profiles = make(map[string]GamepadProfile)
+	}
+	p.profiles[strings.ToLower(guid)] = profile
+}
+
+// Remove removes the profile of the gamepads with guid, which then use the
+// default profile.
+func (p *GamepadProfiles) Remove(guid string) {
+	delete(p.profiles, strings.ToLower(guid))
+}
+
+// Profile returns the profile of the gamepads with guid.
+func (p *GamepadProfiles) Profile(guid string) GamepadProfile {
+	if profile, exist := p.profiles[strings.ToLower(guid)]; exist {
+		return profile
+	}
+	return p.Default
+}
+
+// GamepadProcessor processes the state of a joystick once per frame with the
+// profile of its GUID, and tracks the digital states of its sticks and
+// triggers with hysteresis.
+type GamepadProcessor struct {
+	joystick Joystick
+	profiles *GamepadProfiles
+
+	state ProcessedGamepadState
+	axes  []float32
+	ok    bool
+}
+
+// NewGamepadProcessor returns a GamepadProcessor of j using the profiles of
+// profiles. Changes to profiles take effect on the next update.
+func NewGamepadProcessor(j Joystick, profiles *GamepadProfiles) *GamepadProcessor {
+	return &GamepadProcessor{joystick: j, profiles: profiles}
+}
+
+// Update polls and processes the state of the joystick. It is usually called
+// once per frame, after the events have been processed.
+//
+// This function must only be called from the main thread.
+func (p *GamepadProcessor) Update() {
+	if !p.joystick.Present() {
+		p.state, p.axes, p.ok = ProcessedGamepadState{}, nil, false
+		return
+	}
+	profile := p.profiles.Profile(p.joystick.GetGUID())
+	p.axes = profile.ProcessAxes(p.joystick.GetAxes())
+
+	state, ok := p.joystick.GetGamepadState()
+	if !ok {
+		p.state, p.ok = ProcessedGamepadState{}, false
+		return
+	}
+	previous := p.state
+	p.state = profile.process(state)
+	p.state.LeftStickDirection = profile.stickDirection(p.state.LeftStick, previous.LeftStickDirection)
+	p.state.RightStickDirection = profile.stickDirection(p.state.RightStick, previous.RightStickDirection)
+	left := Hysteresis{Press: orDefault(profile.TriggerPress, 0.5), Release: profile.TriggerRelease, down: previous.LeftTriggerDown}
+	right := Hysteresis{Press: left.Press, Release: left.Release, down: previous.RightTriggerDown}
+	p.state.LeftTriggerDown = left.Update(p.state.LeftTrigger)
+	p.state.RightTriggerDown = right.Update(p.state.RightTrigger)
+	p.ok = true
+}
+
+// GamepadState returns the processed gamepad state of the joystick as of the
+// last update. ok is false if the joystick is not present or has no gamepad
+// mapping.
+func (p *GamepadProcessor) GamepadState() (state ProcessedGamepadState, ok bool) {
+	return p.state, p.ok
+}
+
+// Axes returns the processed raw axes of the joystick as of the last update,
+// or nil if the joystick is not present.
+func (p *GamepadProcessor) Axes() []float32 {
+	return p.axes
+}
+
+// rescale returns value rescaled from the range min–max to 0–1, clamped to
+// 0–1. A max of 0 means 1.
+func rescale(value, min, max float32) float32 {
+	max = orDefault(max, 1)
+	if value <= min {
+		return 0
+	}
+	if value >= max || max <= min {
+		return 1
+	}
+	return (value - min) / (max - min)
+}
+
+// curve returns value, in the range 0–1, raised to the power of exponent. An
+// exponent of 0 means 1.
+func curve(value, exponent float32) float32 {
+	if exponent == 0 || exponent == 1 || value == 0 {
+		return value
+	}
+	return float32(math.Pow(float64(value), float64(exponent)))
+}
+
+// orDefault returns value, or def if value is 0.
+func orDefault(value, def float32) float32 {
+	if value == 0 {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package glfw
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// nearly returns whether a and b differ by less than a rounding error.
+func nearly(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-5
+}
+
+// stickAt returns the stick position at length from the center and at angle,
+// in degrees, counterclockwise from the right.
+func stickAt(length, angle float64) StickVector {
+	rad := angle * math.Pi / 180
+	return StickVector{float32(length * math.Cos(rad)), float32(-length * math.Sin(rad))}
+}
+
+func TestRescaleAndCurve(t *testing.T) {
+	rescales := []struct {
+		value, min, max, want float32
+	}{
+		{0.1, 0.2, 0, 0},
+		{0.2, 0.2, 0, 0},
+		{0.6, 0.2, 0, 0.5},
+		{1, 0.2, 0, 1},
+		{0.5, 0.2, 0.8, 0.5},
+		{0.9, 0.2, 0.8, 1},
+		{0.5, 0.4, 0.3, 1},
+	}
+	for _, test := range rescales {
+		if got := rescale(test.value, test.min, test.max); !nearly(got, test.want) {
+			t.Errorf("rescale(%v, %v, %v) = %v, want %v", test.value, test.min, test.max, got, test.want)
+		}
+	}
+
+	curves := []struct {
+		value, exponent, want float32
+	}{
+		{0.5, 0, 0.5},
+		{0.5, 1, 0.5},
+		{0.5, 2, 0.25},
+		{0, 2, 0},
+		{1, 3, 1},
+	}
+	for _, test := range curves {
+		if got := curve(test.value, test.exponent); !nearly(got, test.want) {
+			t.Errorf("curve(%v, %v) = %v, want %v", test.value, test.exponent, got, test.want)
+		}
+	}
+
+	axis := AxisProfile{Deadzone: 0.2, Exponent: 2}
+	if got := axis.Process(-0.6); !nearly(got, -0.25) {
+		t.Errorf("Process(-0.6) = %v, want -0.25", got)
+	}
+}
+
+func TestDeadzoneShapes(t *testing.T) {
+	tests := []struct {
+		shape DeadzoneShape
+		x, y  float32
+		want  StickVector
+	}{
+		{DeadzoneScaledRadial, 0.1, 0.1, StickVector{}},
+		{DeadzoneScaledRadial, 0.6, 0, StickVector{0.5, 0}},
+		{DeadzoneScaledRadial, 0, -1, StickVector{0, -1}},
+		{DeadzoneRadial, 0.1, 0.1, StickVector{}},
+		{DeadzoneRadial, 0.6, 0, StickVector{0.6, 0}},
+		{DeadzoneRadial, 0, -1, StickVector{0, -1}},
+		// The axial deadzone snaps the stick to the axes near the center.
+		{DeadzoneAxial, 0.6, 0.1, StickVector{0.5, 0}},
+		{DeadzoneAxial, -0.6, -0.6, StickVector{-0.5, -0.5}},
+		{DeadzoneAxial, 1, 1, StickVector{float32(math.Sqrt2 / 2), float32(math.Sqrt2 / 2)}},
+	}
+	for _, test := range tests {
+		p := StickProfile{Shape: test.shape, Deadzone: 0.2}
+		got := p.Process(test.x, test.y)
+		if !nearly(got.X, test.want.X) || !nearly(got.Y, test.want.Y) {
+			t.Errorf("shape %d: Process(%v, %v) = %v, want %v", test.shape, test.x, test.y, got, test.want)
+		}
+	}
+}
+
+func TestStickDirection(t *testing.T) {
+	tests := []struct {
+		angle float64
+		want  HatState
+	}{
+		{0, HatRight},
+		{22, HatRight},
+		{23, HatRightUp},
+		{90, HatUp},
+		{135, HatLeftUp},
+		{180, HatLeft},
+		{-180, HatLeft},
+		{225, HatLeftDown},
+		{-90, HatDown},
+		{-23, HatRightDown},
+		{-22, HatRight},
+	}
+	for _, test := range tests {
+		if got := stickAt(1, test.angle).Direction(); got != test.want {
+			t.Errorf("Direction() at %v° = %v, want %v", test.angle, got, test.want)
+		}
+	}
+	if got := (StickVector{}).Direction(); got != HatCentered {
+		t.Errorf("Direction() at the center = %v, want HatCentered", got)
+	}
+}
+
+func TestHysteresis(t *testing.T) {
+	tests := []struct {
+		h      Hysteresis
+		values []float32
+		want   []bool
+	}{
+		{Hysteresis{Press: 0.5, Release: 0.3}, []float32{0.4, 0.5, 0.35, 0.29, 0.4}, []bool{false, true, true, false, false}},
+		// A zero Release is derived from Press.
+		{Hysteresis{Press: 0.5}, []float32{0.5, 0.41, 0.39}, []bool{true, true, false}},
+		{Hysteresis{Press: 0.9}, []float32{0.9, 0.73, 0.71}, []bool{true, true, false}},
+		// A Release above Press is clamped to it.
+		{Hysteresis{Press: 0.5, Release: 0.7}, []float32{0.5, 0.6, 0.49}, []bool{true, true, false}},
+	}
+	for _, test := range tests {
+		h := test.h
+		for i, value := range test.values {
+			if got := h.Update(value); got != test.want[i] || h.Down() != got {
+				t.Errorf("%+v: Update(%v) = %v, want %v", test.h, value, got, test.want[i])
+			}
+		}
+	}
+}
+
+func TestStickDirectionHysteresis(t *testing.T) {
+	p := DefaultGamepadProfile
+	tests := []struct {
+		v        StickVector
+		previous HatState
+		want     HatState
+	}{
+		{stickAt(1, 25), HatCentered, HatRightUp},
+		// The previous direction is kept near the edge of its sector.
+		{stickAt(1, 25), HatRight, HatRight},
+		{stickAt(1, 20), HatRightUp, HatRightUp},
+		{stickAt(1, 35), HatRight, HatRightUp},
+		{stickAt(1, 90), HatRight, HatUp},
+		{stickAt(1, -170), HatLeft, HatLeft},
+		{stickAt(1, 170), HatLeftDown, HatLeft},
+		// The stick is released below the release distance.
+		{stickAt(0.45, 0), HatCentered, HatCentered},
+		{stickAt(0.45, 0), HatRight, HatRight},
+		{stickAt(0.35, 0), HatRight, HatCentered},
+	}
+	for _, test := range tests {
+		if got := p.stickDirection(test.v, test.previous); got != test.want {
+			t.Errorf("stickDirection(%v, %v) = %v, want %v", test.v, test.previous, got, test.want)
+		}
+	}
+}
+
+func TestGamepadProcessor(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	Joystick1.InjectConnect("Test Pad", testGUID)
+	defer Joystick1.InjectDisconnect()
+
+	profiles := NewGamepadProfiles()
+	profile := DefaultGamepadProfile
+	profile.LeftTrigger = AxisProfile{}
+	profile.TriggerPress = 0.6
+	profiles.Set(strings.ToUpper(testGUID), profile)
+	processor := NewGamepadProcessor(Joystick1, profiles)
+
+	var state GamepadState
+	update := func(trigger float32, stick StickVector) ProcessedGamepadState {
+		state.Axes[GamepadAxisLeftTrigger] = trigger*2 - 1
+		state.Axes[GamepadAxisLeftX], state.Axes[GamepadAxisLeftY] = stick.X, stick.Y
+		Joystick1.InjectGamepadState(&state)
+		processor.Update()
+		processed, ok := processor.GamepadState()
+		if !ok {
+			t.Fatal("GamepadState() of a connected gamepad not ok")
+		}
+		return processed
+	}
+
+	tests := []struct {
+		trigger     float32
+		stick       StickVector
+		wantTrigger bool
+		wantStick   HatState
+	}{
+		{0.55, stickAt(1, 0), false, HatRight},
+		{0.65, stickAt(1, 25), true, HatRight},
+		{0.5, stickAt(1, 35), true, HatRightUp},
+		{0.47, stickAt(1, 20), false, HatRightUp},
+		{0, StickVector{}, false, HatCentered},
+	}
+	for i, test := range tests {
+		processed := update(test.trigger, test.stick)
+		if processed.LeftTriggerDown != test.wantTrigger || processed.LeftStickDirection != test.wantStick {
+			t.Errorf("update %d: trigger down %v, stick %v, want %v, %v", i, processed.LeftTriggerDown, processed.LeftStickDirection, test.wantTrigger, test.wantStick)
+		}
+	}
+
+	Joystick1.InjectDisconnect()
+	processor.Update()
+	if _, ok := processor.GamepadState(); ok || processor.Axes() != nil {
+		t.Error("GamepadState() of a disconnected gamepad still ok")
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build ignore
+
+// This program generates gamecontrollerdb.go from a gamecontrollerdb.txt file
+// of the community SDL_GameControllerDB project
+// (https://github.com/gabomdq/SDL_GameControllerDB), or from the mappings.h
+// file of GLFW. It is invoked by go generate:
+//
+//	go run gen_gamecontrollerdb.go [-o gamecontrollerdb.go] -rev revision [source]
+//
+// revision identifies the version of the mappings, and is recorded in the
+// generated file. source is the path or the http(s) URL of
+// gamecontrollerdb.txt or mappings.h. If source is omitted, revision must be a
+// commit of SDL_GameControllerDB, and gamecontrollerdb.txt is downloaded at
+// that commit:
+//
+//	go run gen_gamecontrollerdb.go -rev 1a2b3c4
+//
+// go generate embeds the mappings bundled with the vendored GLFW sources, so
+// that the generated file can be reproduced offline.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// commitSource is the URL of gamecontrollerdb.txt at a commit of
+// SDL_GameControllerDB.
+const commitSource = "https://raw.githubusercontent.com/gabomdq/SDL_GameControllerDB/%s/gamecontrollerdb.txt"
+
+func main() {
+	output := flag.String("o", "gamecontrollerdb.go", "output file")
+	rev := flag.String("rev", "", "revision of the mappings, e.g. a commit of SDL_GameControllerDB")
+	flag.Parse()
+	if *rev == "" {
+		log.Fatal("-rev is required")
+	}
+	source := fmt.Sprintf(commitSource, *rev)
+	if flag.NArg() > 0 {
+		source = flag.Arg(0)
+	}
+	bundled := strings.HasSuffix(source, ".h")
+
+	r, err := open(source)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer r.Close()
+
+	var mappings []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if bundled {
+			// Mappings are the string literals of the _glfwDefaultMappings
+			// array.
+			if !strings.HasPrefix(line, "\"") || !strings.HasSuffix(line, "\",") {
+				continue
+			}
+			line = strings.TrimSuffix(strings.TrimPrefix(line, "\""), "\",")
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.Contains(line, "`") {
+			log.Fatalf("mapping contains a backquote: %s", line)
+		}
+		mappings = append(mappings, line)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by gen_gamecontrollerdb.go from %s (%s); DO NOT EDIT.\n\n", source, *rev)
+	fmt.Fprintf(&buf, "package glfw\n\n")
+	if bundled {
+		fmt.Fprintf(&buf, "// GameControllerDB is the embedded copy of the gamepad mappings bundled with\n")
+		fmt.Fprintf(&buf, "// %s, a subset of the community SDL_GameControllerDB for all platforms,\n", *rev)
+	} else {
+		fmt.Fprintf(&buf, "// GameControllerDB is the embedded copy of the community SDL_GameControllerDB\n")
+		fmt.Fprintf(&buf, "// at revision %s, with the gamepad mappings for all platforms,\n", *rev)
+	}
+	fmt.Fprintf(&buf, "// one mapping per line. It can be loaded with\n")
+	fmt.Fprintf(&buf, "// Context.LoadDefaultGamepadMappings().\n")
+	fmt.Fprintf(&buf, "const GameControllerDB = `%s\n`\n", strings.Join(mappings, "\n"))
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// open opens source, a path or an http(s) URL.
+func open(source string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.Open(source)
+	}
+	resp, err := http.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", source, resp.Status)
+	}
+	return resp.Body, nil
+}
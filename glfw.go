@@ -17,14 +17,27 @@ package glfw
 #cgo darwin LDFLAGS: -framework Cocoa -framework OpenGL -framework IOKit -framework CoreVideo
 
 // Linux build tags.
-#cgo linux,!wayland CFLAGS: -D_GLFW_X11
-#cgo linux,wayland CFLAGS: -D_GLFW_WAYLAND -D_GNU_SOURCE
-#cgo linux,!wayland LDFLAGS: -lGL -lX11 -lXrandr -lXxf86vm -lXi -lXcursor -lm -lXinerama -ldl -lrt
-#cgo linux,wayland LDFLAGS: -lGL -lwayland-client -lwayland-cursor -lwayland-egl -lxkbcommon -lm -ldl -lrt
+#cgo linux,!wayland,!glfw_null CFLAGS: -D_GLFW_X11
+#cgo linux,wayland,!glfw_null CFLAGS: -D_GLFW_WAYLAND -D_GNU_SOURCE
+#cgo linux,!wayland,!glfw_null LDFLAGS: -lGL -lX11 -lXrandr -lXxf86vm -lXi -lXcursor -lm -lXinerama -ldl -lrt
+#cgo linux,wayland,!glfw_null LDFLAGS: -lGL -lwayland-client -lwayland-cursor -lwayland-egl -lxkbcommon -lm -ldl -lrt
 
+// Null platform build tags.
+#cgo linux,glfw_null CFLAGS: -D_GLFW_OSMESA
+#cgo linux,glfw_null LDFLAGS: -lm -ldl -lrt
+
+#include <stdint.h>
 #include <stdlib.h>
 #include <string.h>
 #include "glfw/include/GLFW/glfw3.h"
+#include "inject.h"
+
+// The Go callbacks of windows are passed the handle of the window, stored as
+// its user pointer. See windowState. While event batching is enabled, the
+// events are appended to the batch instead, see Context.SetEventBatching().
+static uintptr_t goGetWindowHandle(GLFWwindow* window) {
+	return (uintptr_t)glfwGetWindowUserPointer(window);
+}
 
 // Error callback.
 
@@ -59,10 +72,19 @@ static void goRemoveMonitorCallback() {
 
 // Window position callback.
 
-void _windowPosCallback(GLFWwindow*, int, int);
+void _windowPosCallback(GLFWwindow*, uintptr_t, int, int);
+
+int goBatchWindowPos(GLFWwindow*, uintptr_t, int, int);
+
+static void _windowPosCallbackHandle(GLFWwindow* window, int x, int y) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchWindowPos(window, handle, x, y)) {
+		_windowPosCallback(window, handle, x, y);
+	}
+}
 
 static void goSetWindowPosCallback(GLFWwindow* window) {
-	glfwSetWindowPosCallback(window, _windowPosCallback);
+	glfwSetWindowPosCallback(window, _windowPosCallbackHandle);
 }
 
 static void goRemoveWindowPosCallback(GLFWwindow* window) {
@@ -71,10 +93,19 @@ static void goRemoveWindowPosCallback(GLFWwindow* window) {
 
 // Window size callback.
 
-void _windowSizeCallback(GLFWwindow*, int, int);
+void _windowSizeCallback(GLFWwindow*, uintptr_t, int, int);
+
+int goBatchWindowSize(GLFWwindow*, uintptr_t, int, int);
+
+static void _windowSizeCallbackHandle(GLFWwindow* window, int width, int height) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchWindowSize(window, handle, width, height)) {
+		_windowSizeCallback(window, handle, width, height);
+	}
+}
 
 static void goSetWindowSizeCallback(GLFWwindow* window) {
-	glfwSetWindowSizeCallback(window, _windowSizeCallback);
+	glfwSetWindowSizeCallback(window, _windowSizeCallbackHandle);
 }
 
 static void goRemoveWindowSizeCallback(GLFWwindow* window) {
@@ -83,10 +114,19 @@ static void goRemoveWindowSizeCallback(GLFWwindow* window) {
 
 // Window close callback.
 
-void _windowCloseCallback(GLFWwindow*);
+void _windowCloseCallback(GLFWwindow*, uintptr_t);
+
+int goBatchWindowClose(GLFWwindow*, uintptr_t);
+
+static void _windowCloseCallbackHandle(GLFWwindow* window) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchWindowClose(window, handle)) {
+		_windowCloseCallback(window, handle);
+	}
+}
 
 static void goSetWindowCloseCallback(GLFWwindow* window) {
-	glfwSetWindowCloseCallback(window, _windowCloseCallback);
+	glfwSetWindowCloseCallback(window, _windowCloseCallbackHandle);
 }
 
 static void goRemoveWindowCloseCallback(GLFWwindow* window) {
@@ -95,10 +135,19 @@ static void goRemoveWindowCloseCallback(GLFWwindow* window) {
 
 // Window refresh callback.
 
-void _windowRefreshCallback(GLFWwindow*);
+void _windowRefreshCallback(GLFWwindow*, uintptr_t);
+
+int goBatchWindowRefresh(GLFWwindow*, uintptr_t);
+
+static void _windowRefreshCallbackHandle(GLFWwindow* window) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchWindowRefresh(window, handle)) {
+		_windowRefreshCallback(window, handle);
+	}
+}
 
 static void goSetWindowRefreshCallback(GLFWwindow* window) {
-	glfwSetWindowRefreshCallback(window, _windowRefreshCallback);
+	glfwSetWindowRefreshCallback(window, _windowRefreshCallbackHandle);
 }
 
 static void goRemoveWindowRefreshCallback(GLFWwindow* window) {
@@ -107,10 +156,19 @@ static void goRemoveWindowRefreshCallback(GLFWwindow* window) {
 
 // Window focus callback.
 
-void _windowFocusCallback(GLFWwindow*, int);
+void _windowFocusCallback(GLFWwindow*, uintptr_t, int);
+
+int goBatchWindowFocus(GLFWwindow*, uintptr_t, int);
+
+static void _windowFocusCallbackHandle(GLFWwindow* window, int focused) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchWindowFocus(window, handle, focused)) {
+		_windowFocusCallback(window, handle, focused);
+	}
+}
 
 static void goSetWindowFocusCallback(GLFWwindow* window) {
-	glfwSetWindowFocusCallback(window, _windowFocusCallback);
+	glfwSetWindowFocusCallback(window, _windowFocusCallbackHandle);
 }
 
 static void goRemoveWindowFocusCallback(GLFWwindow* window) {
@@ -119,10 +177,19 @@ static void goRemoveWindowFocusCallback(GLFWwindow* window) {
 
 // Window iconify callback.
 
-void _windowIconifyCallback(GLFWwindow*, int);
+void _windowIconifyCallback(GLFWwindow*, uintptr_t, int);
+
+int goBatchWindowIconify(GLFWwindow*, uintptr_t, int);
+
+static void _windowIconifyCallbackHandle(GLFWwindow* window, int iconified) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchWindowIconify(window, handle, iconified)) {
+		_windowIconifyCallback(window, handle, iconified);
+	}
+}
 
 static void goSetWindowIconifyCallback(GLFWwindow* window) {
-	glfwSetWindowIconifyCallback(window, _windowIconifyCallback);
+	glfwSetWindowIconifyCallback(window, _windowIconifyCallbackHandle);
 }
 
 static void goRemoveWindowIconifyCallback(GLFWwindow* window) {
@@ -131,10 +198,19 @@ static void goRemoveWindowIconifyCallback(GLFWwindow* window) {
 
 // Window maximize callback.
 
-void _windowMaximizeCallback(GLFWwindow*, int);
+void _windowMaximizeCallback(GLFWwindow*, uintptr_t, int);
+
+int goBatchWindowMaximize(GLFWwindow*, uintptr_t, int);
+
+static void _windowMaximizeCallbackHandle(GLFWwindow* window, int maximized) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchWindowMaximize(window, handle, maximized)) {
+		_windowMaximizeCallback(window, handle, maximized);
+	}
+}
 
 static void goSetWindowMaximizeCallback(GLFWwindow* window) {
-	glfwSetWindowMaximizeCallback(window, _windowMaximizeCallback);
+	glfwSetWindowMaximizeCallback(window, _windowMaximizeCallbackHandle);
 }
 
 static void goRemoveWindowMaximizeCallback(GLFWwindow* window) {
@@ -143,10 +219,19 @@ static void goRemoveWindowMaximizeCallback(GLFWwindow* window) {
 
 // Framebuffer size callback.
 
-void _framebufferSizeCallback(GLFWwindow*, int, int);
+void _framebufferSizeCallback(GLFWwindow*, uintptr_t, int, int);
+
+int goBatchFramebufferSize(GLFWwindow*, uintptr_t, int, int);
+
+static void _framebufferSizeCallbackHandle(GLFWwindow* window, int width, int height) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchFramebufferSize(window, handle, width, height)) {
+		_framebufferSizeCallback(window, handle, width, height);
+	}
+}
 
 static void goSetFramebufferSizeCallback(GLFWwindow* window) {
-	glfwSetFramebufferSizeCallback(window, _framebufferSizeCallback);
+	glfwSetFramebufferSizeCallback(window, _framebufferSizeCallbackHandle);
 }
 
 static void goRemoveFramebufferSizeCallback(GLFWwindow* window) {
@@ -155,10 +240,19 @@ static void goRemoveFramebufferSizeCallback(GLFWwindow* window) {
 
 // Window content scale callback.
 
-void _windowContentScaleCallback(GLFWwindow*, float, float);
+void _windowContentScaleCallback(GLFWwindow*, uintptr_t, float, float);
+
+int goBatchWindowContentScale(GLFWwindow*, uintptr_t, float, float);
+
+static void _windowContentScaleCallbackHandle(GLFWwindow* window, float xscale, float yscale) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchWindowContentScale(window, handle, xscale, yscale)) {
+		_windowContentScaleCallback(window, handle, xscale, yscale);
+	}
+}
 
 static void goSetWindowContentScaleCallback(GLFWwindow* window) {
-	glfwSetWindowContentScaleCallback(window, _windowContentScaleCallback);
+	glfwSetWindowContentScaleCallback(window, _windowContentScaleCallbackHandle);
 }
 
 static void goRemoveWindowContentScaleCallback(GLFWwindow* window) {
@@ -167,10 +261,19 @@ static void goRemoveWindowContentScaleCallback(GLFWwindow* window) {
 
 // Key callback.
 
-void _keyCallback(GLFWwindow*, int, int, int, int);
+void _keyCallback(GLFWwindow*, uintptr_t, int, int, int, int);
+
+int goBatchKey(GLFWwindow*, uintptr_t, int, int, int, int);
+
+static void _keyCallbackHandle(GLFWwindow* window, int key, int scancode, int action, int mods) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchKey(window, handle, key, scancode, action, mods)) {
+		_keyCallback(window, handle, key, scancode, action, mods);
+	}
+}
 
 static void goSetKeyCallback(GLFWwindow* window) {
-	glfwSetKeyCallback(window, _keyCallback);
+	glfwSetKeyCallback(window, _keyCallbackHandle);
 }
 
 static void goRemoveKeyCallback(GLFWwindow* window) {
@@ -179,10 +282,19 @@ static void goRemoveKeyCallback(GLFWwindow* window) {
 
 // Char callback.
 
-void _charCallback(GLFWwindow*, unsigned int);
+void _charCallback(GLFWwindow*, uintptr_t, unsigned int);
+
+int goBatchChar(GLFWwindow*, uintptr_t, unsigned int);
+
+static void _charCallbackHandle(GLFWwindow* window, unsigned int codepoint) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchChar(window, handle, codepoint)) {
+		_charCallback(window, handle, codepoint);
+	}
+}
 
 static void goSetCharCallback(GLFWwindow* window) {
-	glfwSetCharCallback(window, _charCallback);
+	glfwSetCharCallback(window, _charCallbackHandle);
 }
 
 static void goRemoveCharCallback(GLFWwindow* window) {
@@ -191,10 +303,19 @@ static void goRemoveCharCallback(GLFWwindow* window) {
 
 // Char mods callback.
 
-void _charModsCallback(GLFWwindow*, unsigned int, int);
+void _charModsCallback(GLFWwindow*, uintptr_t, unsigned int, int);
+
+int goBatchCharMods(GLFWwindow*, uintptr_t, unsigned int, int);
+
+static void _charModsCallbackHandle(GLFWwindow* window, unsigned int codepoint, int mods) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchCharMods(window, handle, codepoint, mods)) {
+		_charModsCallback(window, handle, codepoint, mods);
+	}
+}
 
 static void goSetCharModsCallback(GLFWwindow* window) {
-	glfwSetCharModsCallback(window, _charModsCallback);
+	glfwSetCharModsCallback(window, _charModsCallbackHandle);
 }
 
 static void goRemoveCharModsCallback(GLFWwindow* window) {
@@ -203,10 +324,19 @@ static void goRemoveCharModsCallback(GLFWwindow* window) {
 
 // Mouse button callback.
 
-void _mouseButtonCallback(GLFWwindow*, int, int, int);
+void _mouseButtonCallback(GLFWwindow*, uintptr_t, int, int, int);
+
+int goBatchMouseButton(GLFWwindow*, uintptr_t, int, int, int);
+
+static void _mouseButtonCallbackHandle(GLFWwindow* window, int button, int action, int mods) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchMouseButton(window, handle, button, action, mods)) {
+		_mouseButtonCallback(window, handle, button, action, mods);
+	}
+}
 
 static void goSetMouseButtonCallback(GLFWwindow* window) {
-	glfwSetMouseButtonCallback(window, _mouseButtonCallback);
+	glfwSetMouseButtonCallback(window, _mouseButtonCallbackHandle);
 }
 
 static void goRemoveMouseButtonCallback(GLFWwindow* window) {
@@ -215,10 +345,19 @@ static void goRemoveMouseButtonCallback(GLFWwindow* window) {
 
 // Cursor position callback.
 
-void _cursorPosCallback(GLFWwindow*, double, double);
+void _cursorPosCallback(GLFWwindow*, uintptr_t, double, double);
+
+int goBatchCursorPos(GLFWwindow*, uintptr_t, double, double);
+
+static void _cursorPosCallbackHandle(GLFWwindow* window, double x, double y) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchCursorPos(window, handle, x, y)) {
+		_cursorPosCallback(window, handle, x, y);
+	}
+}
 
 static void goSetCursorPosCallback(GLFWwindow* window) {
-	glfwSetCursorPosCallback(window, _cursorPosCallback);
+	glfwSetCursorPosCallback(window, _cursorPosCallbackHandle);
 }
 
 static void goRemoveCursorPosCallback(GLFWwindow* window) {
@@ -227,10 +366,19 @@ static void goRemoveCursorPosCallback(GLFWwindow* window) {
 
 // Cursor enter callback.
 
-void _cursorEnterCallback(GLFWwindow*, int);
+void _cursorEnterCallback(GLFWwindow*, uintptr_t, int);
+
+int goBatchCursorEnter(GLFWwindow*, uintptr_t, int);
+
+static void _cursorEnterCallbackHandle(GLFWwindow* window, int entered) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchCursorEnter(window, handle, entered)) {
+		_cursorEnterCallback(window, handle, entered);
+	}
+}
 
 static void goSetCursorEnterCallback(GLFWwindow* window) {
-	glfwSetCursorEnterCallback(window, _cursorEnterCallback);
+	glfwSetCursorEnterCallback(window, _cursorEnterCallbackHandle);
 }
 
 static void goRemoveCursorEnterCallback(GLFWwindow* window) {
@@ -239,10 +387,19 @@ static void goRemoveCursorEnterCallback(GLFWwindow* window) {
 
 // Scroll callback.
 
-void _scrollCallback(GLFWwindow*, double, double);
+void _scrollCallback(GLFWwindow*, uintptr_t, double, double);
+
+int goBatchScroll(GLFWwindow*, uintptr_t, double, double);
+
+static void _scrollCallbackHandle(GLFWwindow* window, double xoffset, double yoffset) {
+	uintptr_t handle = goGetWindowHandle(window);
+	if (!goBatchScroll(window, handle, xoffset, yoffset)) {
+		_scrollCallback(window, handle, xoffset, yoffset);
+	}
+}
 
 static void goSetScrollCallback(GLFWwindow* window) {
-	glfwSetScrollCallback(window, _scrollCallback);
+	glfwSetScrollCallback(window, _scrollCallbackHandle);
 }
 
 static void goRemoveScrollCallback(GLFWwindow* window) {
@@ -251,16 +408,12 @@ static void goRemoveScrollCallback(GLFWwindow* window) {
 
 // Drop callback.
 
-void _dropCallback(GLFWwindow*, int, char**);
+void _dropCallback(GLFWwindow*, uintptr_t, int, char**);
 
-// Workaround due to that Go does not support const function params.
+// Workaround due to that Go does not support const function params. The paths
+// are only read by _dropCallback.
 static void _dropCallbackConst(GLFWwindow* window, int count, const char** paths) {
-	char** paths_ = malloc(count * sizeof(char*));
-	for (int i = 0; i < count; i++) {
-		paths_[i] = strdup(paths[i]);
-	}
-	_dropCallback(window, count, paths_);
-	free(paths_);
+	_dropCallback(window, goGetWindowHandle(window), count, (char**)paths);
 }
 
 static void goSetDropCallback(GLFWwindow* window) {
@@ -271,6 +424,28 @@ static void goRemoveDropCallback(GLFWwindow* window) {
 	glfwSetDropCallback(window, NULL);
 }
 
+// All window callbacks, set while events are enabled.
+
+static void goSetWindowEventCallbacks(GLFWwindow* window) {
+	glfwSetWindowPosCallback(window, _windowPosCallbackHandle);
+	glfwSetWindowSizeCallback(window, _windowSizeCallbackHandle);
+	glfwSetWindowCloseCallback(window, _windowCloseCallbackHandle);
+	glfwSetWindowRefreshCallback(window, _windowRefreshCallbackHandle);
+	glfwSetWindowFocusCallback(window, _windowFocusCallbackHandle);
+	glfwSetWindowIconifyCallback(window, _windowIconifyCallbackHandle);
+	glfwSetWindowMaximizeCallback(window, _windowMaximizeCallbackHandle);
+	glfwSetFramebufferSizeCallback(window, _framebufferSizeCallbackHandle);
+	glfwSetWindowContentScaleCallback(window, _windowContentScaleCallbackHandle);
+	glfwSetKeyCallback(window, _keyCallbackHandle);
+	glfwSetCharCallback(window, _charCallbackHandle);
+	glfwSetCharModsCallback(window, _charModsCallbackHandle);
+	glfwSetMouseButtonCallback(window, _mouseButtonCallbackHandle);
+	glfwSetCursorPosCallback(window, _cursorPosCallbackHandle);
+	glfwSetCursorEnterCallback(window, _cursorEnterCallbackHandle);
+	glfwSetScrollCallback(window, _scrollCallbackHandle);
+	glfwSetDropCallback(window, _dropCallbackConst);
+}
+
 // Joystick callback.
 
 void _joystickCallback(int, int);
@@ -282,9 +457,41 @@ static void goSetJoystickCallback() {
 static void goRemoveJoystickCallback() {
 	glfwSetJoystickCallback(NULL);
 }
+
+// Vulkan functions.
+
+// glfw3.h only declares the Vulkan functions if the Vulkan header is included
+// before it. The handle types are declared here the same way as vulkan.h does,
+// and the handles are passed from Go as uintptr_t.
+typedef struct VkInstance_T* VkInstance;
+typedef struct VkPhysicalDevice_T* VkPhysicalDevice;
+typedef uint64_t VkSurfaceKHR;
+typedef int VkResult;
+typedef struct VkAllocationCallbacks VkAllocationCallbacks;
+
+GLFWAPI GLFWvkproc glfwGetInstanceProcAddress(VkInstance instance, const char* procname);
+GLFWAPI int glfwGetPhysicalDevicePresentationSupport(VkInstance instance, VkPhysicalDevice device, uint32_t queuefamily);
+GLFWAPI VkResult glfwCreateWindowSurface(VkInstance instance, GLFWwindow* window, const VkAllocationCallbacks* allocator, VkSurfaceKHR* surface);
+
+static void* goGetInstanceProcAddress(uintptr_t instance, const char* procname) {
+	return (void*) glfwGetInstanceProcAddress((VkInstance) instance, procname);
+}
+
+static int goGetPhysicalDevicePresentationSupport(uintptr_t instance, uintptr_t device, uint32_t queuefamily) {
+	return glfwGetPhysicalDevicePresentationSupport((VkInstance) instance, (VkPhysicalDevice) device, queuefamily);
+}
+
+static VkResult goCreateWindowSurface(uintptr_t instance, GLFWwindow* window, uintptr_t allocator, VkSurfaceKHR* surface) {
+	return glfwCreateWindowSurface((VkInstance) instance, window, (const VkAllocationCallbacks*) allocator, surface);
+}
 */
 import "C"
-import "unsafe"
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"unsafe"
+)
 
 // GLFW version constants.
 const (
@@ -332,6 +539,53 @@ const (
 	HatLeftDown  HatState = HatLeft | HatDown
 )
 
+// Up returns whether the hat is pushed up, including the diagonal states
+// HatRightUp and HatLeftUp.
+func (state HatState) Up() bool {
+	return state&HatUp != 0
+}
+
+// Down returns whether the hat is pushed down, including the diagonal states
+// HatRightDown and HatLeftDown.
+func (state HatState) Down() bool {
+	return state&HatDown != 0
+}
+
+// Left returns whether the hat is pushed left, including the diagonal states
+// HatLeftUp and HatLeftDown.
+func (state HatState) Left() bool {
+	return state&HatLeft != 0
+}
+
+// Right returns whether the hat is pushed right, including the diagonal states
+// HatRightUp and HatRightDown.
+func (state HatState) Right() bool {
+	return state&HatRight != 0
+}
+
+// Direction returns the unit vector of the direction the hat is pushed to.
+// Like the gamepad axes, the X-axis points to the right and the Y-axis points
+// down. Diagonal states are normalized to a length of one, and (0, 0) is
+// returned if the hat is centered.
+func (state HatState) Direction() (x, y float32) {
+	if state.Right() {
+		x++
+	}
+	if state.Left() {
+		x--
+	}
+	if state.Down() {
+		y++
+	}
+	if state.Up() {
+		y--
+	}
+	if x != 0 && y != 0 {
+		x, y = x*math.Sqrt2/2, y*math.Sqrt2/2
+	}
+	return
+}
+
 // Key is a keyboard key.
 //
 // These key codes are inspired by the _USB HID Usage Tables v1.12_ (p. 53-60),
@@ -732,6 +986,68 @@ const (
 	NoWindowContext Error = 0x0001000A
 )
 
+// errorDescriptions are the generic descriptions of the error codes, as used by
+// GLFW when no more specific description is available.
+var errorDescriptions = map[Error]string{
+	NoError:            "no error",
+	NotInitialized:     "the GLFW library is not initialized",
+	NoCurrentContext:   "there is no current context",
+	InvalidEnum:        "invalid argument for enum parameter",
+	InvalidValue:       "invalid value for parameter",
+	OutOfMemory:        "out of memory",
+	APIUnavailable:     "the requested API is unavailable",
+	VersionUnavailable: "the requested API version is unavailable",
+	PlatformError:      "a platform-specific error occurred",
+	FormatUnavailable:  "the requested format is unavailable",
+	NoWindowContext:    "the specified window has no context",
+}
+
+// Error implements the error interface. It returns the generic description of
+// the error code.
+func (err Error) Error() string {
+	if desc, ok := errorDescriptions[err]; ok {
+		return "glfw: " + desc
+	}
+	return fmt.Sprintf("glfw: unknown error 0x%08X", int(err))
+}
+
+// Sentinel errors, for use with errors.Is. A *GLFWError matches the sentinel
+// of its error code.
+var (
+	ErrNotInitialized     error = NotInitialized
+	ErrNoCurrentContext   error = NoCurrentContext
+	ErrInvalidEnum        error = InvalidEnum
+	ErrInvalidValue       error = InvalidValue
+	ErrOutOfMemory        error = OutOfMemory
+	ErrAPIUnavailable     error = APIUnavailable
+	ErrVersionUnavailable error = VersionUnavailable
+	ErrPlatformError      error = PlatformError
+	ErrFormatUnavailable  error = FormatUnavailable
+	ErrNoWindowContext    error = NoWindowContext
+)
+
+// GLFWError is an error reported by GLFW, with the error code and the
+// human-readable description of the error.
+type GLFWError struct {
+	// Code : The error code.
+	Code Error
+	// Description : The UTF-8 encoded description of the error.
+	Description string
+}
+
+func (err *GLFWError) Error() string {
+	if err.Description == "" {
+		return err.Code.Error()
+	}
+	return "glfw: " + err.Description
+}
+
+// Unwrap returns the error code of err, so that errors.Is(err, ErrXxx) reports
+// whether err has the code of the sentinel.
+func (err *GLFWError) Unwrap() error {
+	return err.Code
+}
+
 // Hint is a bit field for creating windows and context.
 type Hint int
 
@@ -1112,7 +1428,6 @@ var (
 	errorCallback    ErrorCallback
 	monitorCallback  MonitorCallback
 	joystickCallback JoystickCallback
-	windowCallbacks  = make(map[*Window]*WindowCallbacks, 0)
 )
 
 // VideoMode describes a single video mode.
@@ -1153,19 +1468,68 @@ type Image struct {
 	Pixels []uint8
 }
 
-func (image *Image) c() *C.GLFWimage {
-	cImage := C.GLFWimage{
-		width:  C.int(image.Width),
-		height: C.int(image.Height),
+// size returns the size in bytes of the pixels of image.
+func (image *Image) size() int {
+	if image.Width <= 0 || image.Height <= 0 {
+		return 0
+	}
+	return 4 * image.Width * image.Height
+}
+
+// maxImagesSize is the maximum total size in bytes of the pixels passed to
+// GLFW in a single call, that of the array the C buffer of cImages() is viewed
+// as.
+const maxImagesSize = 1 << 30
+
+// cImages returns the GLFW images of images, allocated along with their pixels
+// in a single C buffer that must be freed with C.free(). The pixels are copied
+// in bulk, truncated or padded with transparent pixels to the size of each
+// image, so that GLFW never reads past them.
+//
+// If the images are too large, or the buffer cannot be allocated, cImages
+// reports an InvalidValue or OutOfMemory error and returns nil.
+func cImages(images []Image) *C.GLFWimage {
+	var pixelsSize int
+	for i := range images {
+		image := &images[i]
+		if image.Width > 0 && image.Height > 0 && image.Width > (maxImagesSize-pixelsSize)/4/image.Height {
+			inputError(InvalidValue, "Image too large")
+			return nil
+		}
+		pixelsSize += image.size()
+	}
+	headerSize := uintptr(len(images)) * C.sizeof_GLFWimage
+	size := headerSize + uintptr(pixelsSize)
+	buf := C.malloc(C.size_t(size))
+	if buf == nil {
+		inputError(OutOfMemory, "Failed to allocate the images")
+		return nil
 	}
 
-	cPixels := make([]C.uchar, 0, len(image.Pixels))
-	for _, pixel := range image.Pixels {
-		cPixels = append(cPixels, C.uchar(pixel))
+	cImages := (*[1 << 20]C.GLFWimage)(buf)[:len(images):len(images)]
+	pixels := (*[1 << 30]uint8)(unsafe.Pointer(uintptr(buf) + headerSize))[: size-headerSize : size-headerSize]
+	for i := range images {
+		n := images[i].size()
+		cImages[i] = C.GLFWimage{
+			width:  C.int(images[i].Width),
+			height: C.int(images[i].Height),
+		}
+		if n > 0 {
+			packPixels(pixels[:n], images[i].Pixels)
+			cImages[i].pixels = (*C.uchar)(unsafe.Pointer(&pixels[0]))
+			pixels = pixels[n:]
+		}
 	}
-	cImage.pixels = &cPixels[0]
+	return (*C.GLFWimage)(buf)
+}
 
-	return &cImage
+// packPixels copies pixels to dst in bulk, truncated or padded with
+// transparent pixels to the length of dst.
+func packPixels(dst, pixels []uint8) {
+	copied := copy(dst, pixels)
+	for i := copied; i < len(dst); i++ {
+		dst[i] = 0
+	}
 }
 
 // GamepadState describes the input state of a gamepad.
@@ -1226,6 +1590,21 @@ func Init() *Context {
 	return nil
 }
 
+// InitErr is like Init(), but returns the error that caused the initialization
+// to fail instead of nil.
+//
+// This function must only be called from the main thread.
+func InitErr() (*Context, error) {
+	var c *Context
+	err := catchError(func() {
+		c = Init()
+	})
+	if c == nil {
+		return nil, failedError(err, "initialization")
+	}
+	return c, nil
+}
+
 // Terminate terminates the GLFW library.
 //
 // This function destroys all remaining windows and cursors, restores any
@@ -1233,6 +1612,12 @@ func Init() *Context {
 // function is called, you must again call Init() successfully before you will
 // be able to use most GLFW functions.
 //
+// All window, monitor and joystick callbacks are unregistered, the event queue
+// is closed, input states are disabled, synthetic gamepads are disconnected
+// without calling the joystick callback and any recording is stopped, although
+// Recorder.Stop() must still be called to flush it. The error callback remains
+// set.
+//
 // If GLFW has been successfully initialized, this function should be called
 // before the application exits. If initialization fails, there is no need to
 // call this function, as it is called by Init() before it returns failure.
@@ -1247,6 +1632,25 @@ func Init() *Context {
 // This function must only be called from the main thread.
 func (c *Context) Terminate() {
 	C.glfwTerminate()
+	monitorCallback = nil
+	joystickCallback = nil
+	if eventQueue != nil {
+		eventQueue.Close()
+	}
+	recorder = nil
+	inputStates = nil
+	injectedJoysticks = make(map[Joystick]*injectedJoystick)
+	gamepadButtonCallback = nil
+	gamepadAxisCallback = nil
+	gamepadAxisEpsilon = DefaultGamepadAxisEpsilon
+	reportedGamepadStates = [JoystickLast + 1]GamepadState{}
+	monitorListeners = listenerList{}
+	joystickListeners = listenerList{}
+	gamepadButtonListeners = listenerList{}
+	gamepadAxisListeners = listenerList{}
+	eventListeners = false
+	resetWindowStates()
+	resetEventBatch()
 }
 
 // InitHint sets the specified init hint to the desired value.
@@ -1320,11 +1724,51 @@ func GetVersionString() string {
 // This function may be called before Init.
 //
 // This function may be called from any thread.
-//
-// TODO: Currently the description will always be returned as "". Should be
-// implemented.
 func GetError() (Error, string) {
-	return Error(C.glfwGetError((**C.char)(C.NULL))), ""
+	var cDesc *C.char
+	err := Error(C.glfwGetError(&cDesc))
+	return err, C.GoString(cDesc)
+}
+
+// lastError returns and clears the last error for the calling thread as a
+// *GLFWError, or nil if no error has occurred.
+func lastError() error {
+	code, desc := GetError()
+	if code == NoError {
+		return nil
+	}
+	return &GLFWError{Code: code, Description: desc}
+}
+
+// catchError calls fn and returns the last error that occurred during the call,
+// or nil if no error occurred.
+//
+// As GLFW stores the last error per thread, the calling goroutine is locked to
+// its thread for the duration of the call, and any earlier error of the thread
+// is cleared before fn is called.
+func catchError(fn func()) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.glfwGetError(nil)
+	fn()
+	return lastError()
+}
+
+// inputError reports an error detected by the Go side of a call, as if GLFW
+// had emitted it.
+func inputError(code Error, description string) {
+	cDesc := C.CString(description)
+	defer C.free(unsafe.Pointer(cDesc))
+	C.goInputError(C.int(code), cDesc)
+}
+
+// failedError returns err, or a PlatformError if a call that failed did not
+// report any error. what describes the call that failed.
+func failedError(err error, what string) error {
+	if err != nil {
+		return err
+	}
+	return &GLFWError{Code: PlatformError, Description: what + " failed"}
 }
 
 // SetErrorCallback sets the error callback.
@@ -1352,7 +1796,7 @@ func SetErrorCallback(callback ErrorCallback) ErrorCallback {
 	errorCallback = callback
 	if callback != nil {
 		C.goSetErrorCallback()
-	} else {
+	} else if errorListeners.empty() {
 		C.goRemoveErrorCallback()
 	}
 	return previousCallback
@@ -1360,9 +1804,13 @@ func SetErrorCallback(callback ErrorCallback) ErrorCallback {
 
 //export _errorCallback
 func _errorCallback(cErr C.int, cDesc *C.char) {
+	defer recoverCallback()
+	err := Error(cErr)
+	desc := C.GoString(cDesc)
+	if errorListeners.notify(errorEvent{code: err, desc: desc}) {
+		return
+	}
 	if errorCallback != nil {
-		err := Error(cErr)
-		desc := C.GoString(cDesc)
 		errorCallback(err, desc)
 	}
 }
@@ -1552,7 +2000,7 @@ func (c *Context) SetMonitorCallback(callback MonitorCallback) MonitorCallback {
 	monitorCallback = callback
 	if callback != nil {
 		C.goSetMonitorCallback()
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveMonitorCallback()
 	}
 	return previousCallback
@@ -1560,10 +2008,10 @@ func (c *Context) SetMonitorCallback(callback MonitorCallback) MonitorCallback {
 
 //export _monitorCallback
 func _monitorCallback(cMonitor *C.GLFWmonitor, cEvent C.int) {
-	if errorCallback != nil {
-		monitor, event := (*Monitor)(cMonitor), ConnectionEvent(cEvent)
-		monitorCallback(monitor, event)
-	}
+	defer recoverCallback()
+	flushEventBatch()
+	monitor, event := (*Monitor)(cMonitor), ConnectionEvent(cEvent)
+	dispatchEvent(MonitorEvent{Monitor: monitor, Event: event})
 }
 
 // GetVideoModes returns an array of all video modes supported by monitor, or
@@ -1657,18 +2105,14 @@ func (monitor *Monitor) GetGammaRamp() *GammaRamp {
 	if unsafe.Pointer(cRamp) != C.NULL {
 		size := int(cRamp.size)
 		ramp := &GammaRamp{
-			Red:   make([]uint16, 0, size),
-			Green: make([]uint16, 0, size),
-			Blue:  make([]uint16, 0, size),
+			Red:   make([]uint16, size),
+			Green: make([]uint16, size),
+			Blue:  make([]uint16, size),
 		}
-		for i := 0; i < size; i++ {
-			offset := unsafe.Sizeof(*cRamp.red) * uintptr(i)
-			cRed := (*C.ushort)(unsafe.Pointer(uintptr(unsafe.Pointer(cRamp.red)) + offset))
-			cGreen := (*C.ushort)(unsafe.Pointer(uintptr(unsafe.Pointer(cRamp.red)) + offset))
-			cBlue := (*C.ushort)(unsafe.Pointer(uintptr(unsafe.Pointer(cRamp.red)) + offset))
-			ramp.Red = append(ramp.Red, uint16(*cRed))
-			ramp.Green = append(ramp.Green, uint16(*cGreen))
-			ramp.Blue = append(ramp.Blue, uint16(*cBlue))
+		if size > 0 {
+			copy(ramp.Red, (*[1 << 28]uint16)(unsafe.Pointer(cRamp.red))[:size:size])
+			copy(ramp.Green, (*[1 << 28]uint16)(unsafe.Pointer(cRamp.green))[:size:size])
+			copy(ramp.Blue, (*[1 << 28]uint16)(unsafe.Pointer(cRamp.blue))[:size:size])
 		}
 		return ramp
 	}
@@ -1690,7 +2134,8 @@ func (monitor *Monitor) GetGammaRamp() *GammaRamp {
 // Possible errors include NotInitialized and PlatformError.
 //
 // The size of the specified gamma ramp should match the size of the current
-// ramp for that monitor.
+// ramp for that monitor. The three channels should have the same length; the
+// extra values of a longer channel are ignored, and an empty ramp is ignored.
 //
 // On Windows, the gamma ramp size must be 256.
 //
@@ -1699,24 +2144,49 @@ func (monitor *Monitor) GetGammaRamp() *GammaRamp {
 //
 // This function must only be called from the main thread.
 func (monitor *Monitor) SetGammaRamp(ramp *GammaRamp) {
-	size := len(ramp.Red)
-	cRed := make([]C.ushort, 0, size)
-	cGreen := make([]C.ushort, 0, size)
-	cBlue := make([]C.ushort, 0, size)
-	for i := 0; i < int(size); i++ {
-		cRed = append(cRed, C.ushort(ramp.Red[i]))
-		cGreen = append(cGreen, C.ushort(ramp.Green[i]))
-		cBlue = append(cBlue, C.ushort(ramp.Blue[i]))
+	size := ramp.size()
+	if size == 0 {
+		return
 	}
+
+	// The three channels are copied in bulk to a single C buffer, as GLFW
+	// must not be passed Go pointers in a Go-allocated GLFWgammaramp.
+	buf := C.malloc(C.size_t(3 * size * C.sizeof_ushort))
+	defer C.free(buf)
+	channels := (*[1 << 28]uint16)(buf)[: 3*size : 3*size]
+	ramp.pack(channels)
+
 	cRamp := C.GLFWgammaramp{
-		red:   &cRed[0],
-		green: &cGreen[0],
-		blue:  &cBlue[0],
+		red:   (*C.ushort)(unsafe.Pointer(&channels[0])),
+		green: (*C.ushort)(unsafe.Pointer(&channels[size])),
+		blue:  (*C.ushort)(unsafe.Pointer(&channels[2*size])),
 		size:  C.uint(size),
 	}
 	C.glfwSetGammaRamp(monitor.c(), &cRamp)
 }
 
+// size returns the number of entries of ramp passed to GLFW, the length of its
+// shortest channel.
+func (ramp *GammaRamp) size() int {
+	size := len(ramp.Red)
+	if len(ramp.Green) < size {
+		size = len(ramp.Green)
+	}
+	if len(ramp.Blue) < size {
+		size = len(ramp.Blue)
+	}
+	return size
+}
+
+// pack copies the first ramp.size() entries of the red, green and blue
+// channels of ramp to channels in bulk, one channel after the other.
+func (ramp *GammaRamp) pack(channels []uint16) {
+	size := len(channels) / 3
+	copy(channels[:size], ramp.Red)
+	copy(channels[size:2*size], ramp.Green)
+	copy(channels[2*size:], ramp.Blue)
+}
+
 // DefaultWindowHints resets all window hints to their default values.
 //
 // Possible errors include NotInitialized.
@@ -1920,14 +2390,35 @@ func (c *Context) CreateWindow(width, height int, title string, monitor *Monitor
 	cTitle := C.CString(title)
 	defer C.free(unsafe.Pointer(cTitle))
 	cWindow := C.glfwCreateWindow(C.int(width), C.int(height), cTitle, monitor.c(), share.c())
-	if unsafe.Pointer(cWindow) != C.NULL {
-		return (*Window)(cWindow)
+	if unsafe.Pointer(cWindow) == C.NULL {
+		return nil
 	}
-	return nil
+	win := (*Window)(cWindow)
+	newWindowState(win)
+	if eventsEnabled() {
+		enableWindowEvents(win)
+	}
+	return win
+}
+
+// CreateWindowErr is like Context.CreateWindow(), but returns the error that
+// caused the window creation to fail instead of nil.
+//
+// This function must only be called from the main thread.
+func (c *Context) CreateWindowErr(width, height int, title string, monitor *Monitor, share *Window) (*Window, error) {
+	var win *Window
+	err := catchError(func() {
+		win = c.CreateWindow(width, height, title, monitor, share)
+	})
+	if win == nil {
+		return nil, failedError(err, "window creation")
+	}
+	return win, nil
 }
 
 // Destroy destroys win and its context. On calling this function, no further
-// callbacks will be called for that window.
+// callbacks will be called for that window, and all the callbacks set for it
+// are unregistered.
 //
 // If the context of win is current on the main thread, it is detached before
 // being destroyed.
@@ -1941,9 +2432,15 @@ func (c *Context) CreateWindow(width, height int, title string, monitor *Monitor
 //
 // This function must only be called from the main thread.
 func (win *Window) Destroy() {
+	deleteWindowState(win)
 	C.glfwDestroyWindow(win.c())
 }
 
+// enableWindowEvents sets all the GLFW callbacks of win.
+func enableWindowEvents(win *Window) {
+	C.goSetWindowEventCallbacks(win.c())
+}
+
 // ShouldClose returns the value of the close flag of win.
 //
 // Possible errors include NotInitialized.
@@ -1989,13 +2486,16 @@ func (win *Window) SetTitle(title string) {
 //
 // The pixels are 32-bit, little-endian, non-premultiplied RGBA, i.e. eight bits
 // per channel with the red channel first. They are arranged canonically as
-// packed sequential rows, starting from the top-left corner.
+// packed sequential rows, starting from the top-left corner. Missing pixels are
+// transparent.
 //
 // The desired image sizes varies depending on platform and system settings. The
 // selected images will be rescaled as needed. Good sizes include 16x16, 32x32
 // and 48x48.
 //
-// Possible errors include NotInitialized and PlatformError.
+// Possible errors include NotInitialized, InvalidValue, OutOfMemory and
+// PlatformError. InvalidValue is emitted if the images total more than 1 GiB
+// of pixels.
 //
 // On macOS, the GLFW window has no icon, as it is not a document window, so
 // this function does nothing. The dock icon will be the same as the application
@@ -2010,17 +2510,17 @@ func (win *Window) SetTitle(title string) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetIcon(images []Image) {
-	if images == nil || len(images) == 0 {
+	if len(images) == 0 {
 		C.glfwSetWindowIcon(win.c(), 0, (*C.GLFWimage)(C.NULL))
 		return
 	}
 
-	cImages := make([]C.GLFWimage, 0, len(images))
-	for _, image := range images {
-		cImages = append(cImages, *(image.c()))
+	cImages := cImages(images)
+	if cImages == nil {
+		return
 	}
-
-	C.glfwSetWindowIcon(win.c(), C.int(len(images)), &cImages[0])
+	defer C.free(unsafe.Pointer(cImages))
+	C.glfwSetWindowIcon(win.c(), C.int(len(images)), cImages)
 }
 
 // GetPos retrieves the position, in screen coordinates, of the upper-left
@@ -2326,7 +2826,7 @@ func (win *Window) Hide() {
 // Possible errors include NotInitailized and PlatformError.
 //
 // On Wayland, it is not possible for an application to bring its windows to
-// front, this function will always emit @ref PlatformError.
+// front, this function will always emit PlatformError.
 //
 // This function must only be called from the main thread.
 func (win *Window) Focus() {
@@ -2347,6 +2847,9 @@ func (win *Window) Focus() {
 // On macOS, attention is requested to the application as a whole, not the
 // specific window.
 //
+// On Wayland, attention requests are not implemented yet, this function will
+// always emit PlatformError.
+//
 // This function must only be called from the main thread.
 func (win *Window) RequestAttention() {
 	C.glfwRequestWindowAttention(win.c())
@@ -2396,6 +2899,16 @@ func (win *Window) SetMonitor(monitor *Monitor, x, y, width, height, refreshRate
 	C.glfwSetWindowMonitor(win.c(), monitor.c(), C.int(x), C.int(y), C.int(width), C.int(height), C.int(refreshRate))
 }
 
+// SetMonitorErr is like Window.SetMonitor(), but returns the error that
+// occurred, if any.
+//
+// This function must only be called from the main thread.
+func (win *Window) SetMonitorErr(monitor *Monitor, x, y, width, height, refreshRate int) error {
+	return catchError(func() {
+		win.SetMonitor(monitor, x, y, width, height, refreshRate)
+	})
+}
+
 // GetAttrib returns the value of an attribute of win or its OpenGL or OpenGL ES
 // context. Returns zero if an error occurred.
 //
@@ -2441,6 +2954,9 @@ func (win *Window) GetAttribBool(attrib Hint) bool {
 // Calling Window.GetAttrib() will always return the latest value, even if that
 // value is ignored by the current mode of the window.
 //
+// On Wayland, setting the Resizable and Floating attributes is not implemented
+// yet, and will emit PlatformError.
+//
 // This function must only be called from the main thread.
 func (win *Window) SetAttrib(attrib Hint, value bool) {
 	if value {
@@ -2453,11 +2969,16 @@ func (win *Window) SetAttrib(attrib Hint, value bool) {
 // SetUserPointer sets the user-defined pointer of window. The current value is
 // retained until the window is destroyed. The initial value is nil.
 //
+// The pointer is kept by this package rather than by GLFW, as the GLFW user
+// pointer of win holds the handle used to dispatch its callbacks.
+//
 // Possible error include NotInitialized.
 //
 // This function may be called from any thread. Access is not synchronized.
 func (win *Window) SetUserPointer(pointer unsafe.Pointer) {
-	C.glfwSetWindowUserPointer(win.c(), pointer)
+	if state := win.state(); state != nil {
+		state.userPointer = pointer
+	}
 }
 
 // GetUserPointer returns the current value of the user-defined pointer of win.
@@ -2467,7 +2988,10 @@ func (win *Window) SetUserPointer(pointer unsafe.Pointer) {
 //
 // This function may be called from any thread. Access is not synchronized.
 func (win *Window) GetUserPointer() unsafe.Pointer {
-	return unsafe.Pointer(C.glfwGetWindowUserPointer(win.c()))
+	if state := win.state(); state != nil {
+		return state.userPointer
+	}
+	return nil
 }
 
 // SetPosCallback sets the position callback for win, which is called when win
@@ -2486,30 +3010,25 @@ func (win *Window) GetUserPointer() unsafe.Pointer {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetPosCallback(callback WindowPosCallback) WindowPosCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.PosCallback
 	callbacks.PosCallback = callback
 
 	if callback != nil {
 		C.goSetWindowPosCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveWindowPosCallback(win.c())
 	}
 	return previousCallback
 }
 
 //export _windowPosCallback
-func _windowPosCallback(cWin *C.GLFWwindow, cX, cY C.int) {
+func _windowPosCallback(cWin *C.GLFWwindow, handle C.uintptr_t, cX, cY C.int) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.PosCallback != nil {
-		x, y := int(cX), int(cY)
-		callbacks.PosCallback(win, x, y)
-	}
+	x, y := int(cX), int(cY)
+	dispatchEventTo(windowStateOf(handle), WindowPosEvent{Window: win, X: x, Y: y})
 }
 
 // SetSizeCallback sets the size callback of win, which is called when win is
@@ -2525,18 +3044,14 @@ func _windowPosCallback(cWin *C.GLFWwindow, cX, cY C.int) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetSizeCallback(callback WindowSizeCallback) WindowSizeCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.SizeCallback
 	callbacks.SizeCallback = callback
 
 	if callback != nil {
 		C.goSetWindowSizeCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveWindowSizeCallback(win.c())
 	}
 
@@ -2544,12 +3059,11 @@ func (win *Window) SetSizeCallback(callback WindowSizeCallback) WindowSizeCallba
 }
 
 //export _windowSizeCallback
-func _windowSizeCallback(cWin *C.GLFWwindow, cWidth, cHeight C.int) {
+func _windowSizeCallback(cWin *C.GLFWwindow, handle C.uintptr_t, cWidth, cHeight C.int) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.SizeCallback != nil {
-		width, height := int(cWidth), int(cHeight)
-		callbacks.SizeCallback(win, width, height)
-	}
+	width, height := int(cWidth), int(cHeight)
+	dispatchEventTo(windowStateOf(handle), WindowSizeEvent{Window: win, Width: width, Height: height})
 }
 
 // SetCloseCallback sets the close callback of win, which is called when the
@@ -2573,18 +3087,14 @@ func _windowSizeCallback(cWin *C.GLFWwindow, cWidth, cHeight C.int) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetCloseCallback(callback WindowCloseCallback) WindowCloseCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.CloseCallback
 	callbacks.CloseCallback = callback
 
 	if callback != nil {
 		C.goSetWindowCloseCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveWindowCloseCallback(win.c())
 	}
 
@@ -2592,11 +3102,10 @@ func (win *Window) SetCloseCallback(callback WindowCloseCallback) WindowCloseCal
 }
 
 //export _windowCloseCallback
-func _windowCloseCallback(cWin *C.GLFWwindow) {
+func _windowCloseCallback(cWin *C.GLFWwindow, handle C.uintptr_t) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.CloseCallback != nil {
-		callbacks.CloseCallback(win)
-	}
+	dispatchEventTo(windowStateOf(handle), WindowCloseEvent{Window: win})
 }
 
 // SetRefreshCallback sets the refresh callback for win, which is called when
@@ -2616,18 +3125,14 @@ func _windowCloseCallback(cWin *C.GLFWwindow) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetRefreshCallback(callback WindowRefreshCallback) WindowRefreshCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.RefreshCallback
 	callbacks.RefreshCallback = callback
 
 	if callback != nil {
 		C.goSetWindowRefreshCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveWindowRefreshCallback(win.c())
 	}
 
@@ -2635,11 +3140,10 @@ func (win *Window) SetRefreshCallback(callback WindowRefreshCallback) WindowRefr
 }
 
 //export _windowRefreshCallback
-func _windowRefreshCallback(cWin *C.GLFWwindow) {
+func _windowRefreshCallback(cWin *C.GLFWwindow, handle C.uintptr_t) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.RefreshCallback != nil {
-		callbacks.RefreshCallback(win)
-	}
+	dispatchEventTo(windowStateOf(handle), WindowRefreshEvent{Window: win})
 }
 
 // SetFocusCallback sets the focus callback of win, which is called when win
@@ -2659,18 +3163,14 @@ func _windowRefreshCallback(cWin *C.GLFWwindow) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetFocusCallback(callback WindowFocusCallback) WindowFocusCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.FocusCallback
 	callbacks.FocusCallback = callback
 
 	if callback != nil {
 		C.goSetWindowFocusCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveWindowFocusCallback(win.c())
 	}
 
@@ -2678,12 +3178,11 @@ func (win *Window) SetFocusCallback(callback WindowFocusCallback) WindowFocusCal
 }
 
 //export _windowFocusCallback
-func _windowFocusCallback(cWin *C.GLFWwindow, cFocused C.int) {
+func _windowFocusCallback(cWin *C.GLFWwindow, handle C.uintptr_t, cFocused C.int) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.FocusCallback != nil {
-		focused := int(cFocused) == int(True)
-		callbacks.FocusCallback(win, focused)
-	}
+	focused := int(cFocused) == int(True)
+	dispatchEventTo(windowStateOf(handle), FocusEvent{Window: win, Focused: focused})
 }
 
 // SetIconifyCallback sets the iconification callback of win, which is called
@@ -2701,18 +3200,14 @@ func _windowFocusCallback(cWin *C.GLFWwindow, cFocused C.int) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetIconifyCallback(callback WindowIconifyCallback) WindowIconifyCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.IconifyCallback
 	callbacks.IconifyCallback = callback
 
 	if callback != nil {
 		C.goSetWindowIconifyCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveWindowIconifyCallback(win.c())
 	}
 
@@ -2720,12 +3215,11 @@ func (win *Window) SetIconifyCallback(callback WindowIconifyCallback) WindowIcon
 }
 
 //export _windowIconifyCallback
-func _windowIconifyCallback(cWin *C.GLFWwindow, cIconified C.int) {
+func _windowIconifyCallback(cWin *C.GLFWwindow, handle C.uintptr_t, cIconified C.int) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.IconifyCallback != nil {
-		iconified := int(cIconified) == int(True)
-		callbacks.IconifyCallback(win, iconified)
-	}
+	iconified := int(cIconified) == int(True)
+	dispatchEventTo(windowStateOf(handle), IconifyEvent{Window: win, Iconified: iconified})
 }
 
 // SetMaximizeCallback sets the maximize callback for the specified window.
@@ -2742,18 +3236,14 @@ func _windowIconifyCallback(cWin *C.GLFWwindow, cIconified C.int) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetMaximizeCallback(callback WindowMaximizeCallback) WindowMaximizeCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.MaximizeCallback
 	callbacks.MaximizeCallback = callback
 
 	if callback != nil {
 		C.goSetWindowMaximizeCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveWindowMaximizeCallback(win.c())
 	}
 
@@ -2761,12 +3251,11 @@ func (win *Window) SetMaximizeCallback(callback WindowMaximizeCallback) WindowMa
 }
 
 //export _windowMaximizeCallback
-func _windowMaximizeCallback(cWin *C.GLFWwindow, cMaximized C.int) {
+func _windowMaximizeCallback(cWin *C.GLFWwindow, handle C.uintptr_t, cMaximized C.int) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.MaximizeCallback != nil {
-		maximized := int(cMaximized) == int(True)
-		callbacks.MaximizeCallback(win, maximized)
-	}
+	maximized := int(cMaximized) == int(True)
+	dispatchEventTo(windowStateOf(handle), MaximizeEvent{Window: win, Maximized: maximized})
 }
 
 // SetFramebufferSizeCallback sets the framebuffer resize callback of win, which
@@ -2781,18 +3270,14 @@ func _windowMaximizeCallback(cWin *C.GLFWwindow, cMaximized C.int) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetFramebufferSizeCallback(callback FramebufferSizeCallback) FramebufferSizeCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.FramebufferSizeCallback
 	callbacks.FramebufferSizeCallback = callback
 
 	if callback != nil {
 		C.goSetFramebufferSizeCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveFramebufferSizeCallback(win.c())
 	}
 
@@ -2800,12 +3285,11 @@ func (win *Window) SetFramebufferSizeCallback(callback FramebufferSizeCallback)
 }
 
 //export _framebufferSizeCallback
-func _framebufferSizeCallback(cWin *C.GLFWwindow, cWidth, cHeight C.int) {
+func _framebufferSizeCallback(cWin *C.GLFWwindow, handle C.uintptr_t, cWidth, cHeight C.int) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.FramebufferSizeCallback != nil {
-		width, height := int(cWidth), int(cHeight)
-		callbacks.FramebufferSizeCallback(win, width, height)
-	}
+	width, height := int(cWidth), int(cHeight)
+	dispatchEventTo(windowStateOf(handle), FramebufferSizeEvent{Window: win, Width: width, Height: height})
 }
 
 // SetWindowContentScaleCallback sets the window content scale callback for the specified window.
@@ -2822,18 +3306,14 @@ func _framebufferSizeCallback(cWin *C.GLFWwindow, cWidth, cHeight C.int) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetWindowContentScaleCallback(callback WindowContentScaleCallback) WindowContentScaleCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.ContentScaleCallback
 	callbacks.ContentScaleCallback = callback
 
 	if callback != nil {
 		C.goSetWindowContentScaleCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveWindowContentScaleCallback(win.c())
 	}
 
@@ -2841,12 +3321,11 @@ func (win *Window) SetWindowContentScaleCallback(callback WindowContentScaleCall
 }
 
 //export _windowContentScaleCallback
-func _windowContentScaleCallback(cWin *C.GLFWwindow, cXScale, cYScale C.float) {
+func _windowContentScaleCallback(cWin *C.GLFWwindow, handle C.uintptr_t, cXScale, cYScale C.float) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.ContentScaleCallback != nil {
-		xScale, yScale := float32(cXScale), float32(cYScale)
-		callbacks.ContentScaleCallback(win, xScale, yScale)
-	}
+	xScale, yScale := float32(cXScale), float32(cYScale)
+	dispatchEventTo(windowStateOf(handle), ContentScaleEvent{Window: win, XScale: xScale, YScale: yScale})
 }
 
 // PollEvents processes all pending events.
@@ -2869,6 +3348,9 @@ func _windowContentScaleCallback(cWin *C.GLFWwindow, cXScale, cYScale C.float) {
 //
 // Event processing is not required for joystick input to work.
 //
+// If a callback panics, this function panics again with a *CallbackPanic once
+// control is back in Go. See CallbackPanic.
+//
 // Possible errors include NotInitialized and PlatformError.
 //
 // This function must not be called from a callback.
@@ -2876,6 +3358,17 @@ func _windowContentScaleCallback(cWin *C.GLFWwindow, cXScale, cYScale C.float) {
 // This function must only be called from the main thread.
 func (c *Context) PollEvents() {
 	C.glfwPollEvents()
+	endEventProcessing()
+}
+
+// PollEventsErr is like Context.PollEvents(), but returns the panic of a callback as a
+// *CallbackPanic instead of panicking.
+//
+// This function must only be called from the main thread.
+func (c *Context) PollEventsErr() (err error) {
+	defer catchCallbackPanic(&err)
+	c.PollEvents()
+	return nil
 }
 
 // WaitEvents waits until events are queued and processes them.
@@ -2905,6 +3398,9 @@ func (c *Context) PollEvents() {
 //
 // Event processing is not required for joystick input to work.
 //
+// If a callback panics, this function panics again with a *CallbackPanic once
+// control is back in Go. See CallbackPanic.
+//
 // Possible errors include NotInitialized and PlatformError.
 //
 // This function must not be called from a callback.
@@ -2912,6 +3408,17 @@ func (c *Context) PollEvents() {
 // This function must only be called from the main thread.
 func (c *Context) WaitEvents() {
 	C.glfwWaitEvents()
+	endEventProcessing()
+}
+
+// WaitEventsErr is like Context.WaitEvents(), but returns the panic of a callback as a
+// *CallbackPanic instead of panicking.
+//
+// This function must only be called from the main thread.
+func (c *Context) WaitEventsErr() (err error) {
+	defer catchCallbackPanic(&err)
+	c.WaitEvents()
+	return nil
 }
 
 // WaitEventsTimeout waits with timeout until events are queued and processes
@@ -2944,6 +3451,9 @@ func (c *Context) WaitEvents() {
 //
 // Event processing is not required for joystick input to work.
 //
+// If a callback panics, this function panics again with a *CallbackPanic once
+// control is back in Go. See CallbackPanic.
+//
 // timeout is the maximum amount of time, in seconds, to wait.
 //
 // Possible errors include NotInitialized, InvalidValue and PlatformError.
@@ -2953,6 +3463,32 @@ func (c *Context) WaitEvents() {
 // This function must only be called from the main thread.
 func (c *Context) WaitEventsTimeout(timeout float64) {
 	C.glfwWaitEventsTimeout(C.double(timeout))
+	endEventProcessing()
+}
+
+// WaitEventsTimeoutErr is like Context.WaitEventsTimeout(), but returns the panic of a
+// callback as a *CallbackPanic instead of panicking.
+//
+// This function must only be called from the main thread.
+func (c *Context) WaitEventsTimeoutErr(timeout float64) (err error) {
+	defer catchCallbackPanic(&err)
+	c.WaitEventsTimeout(timeout)
+	return nil
+}
+
+// endEventProcessing completes a call to an event processing function once
+// GLFW has processed the events. The batched events are delivered, the
+// gamepads are polled and the frame is recorded before the panic of a
+// callback, if any, is raised, so that a panic does not cut the frame short.
+func endEventProcessing() {
+	flushEventBatch()
+	if gamepadEventsEnabled() {
+		pollGamepadEvents()
+	}
+	if recorder != nil {
+		recorder.endFrame()
+	}
+	raiseCallbackPanic()
 }
 
 // PostEmptyEvent posts an empty event from the current thread to the event
@@ -3164,6 +3700,9 @@ func (win *Window) GetMouseButton(button Button) Action {
 func (win *Window) GetCursorPos() (x, y float64) {
 	var cX, cY C.double
 	C.glfwGetCursorPos(win.c(), &cX, &cY)
+	if virtualCursor {
+		C.goGetVirtualCursorPos(win.c(), &cX, &cY)
+	}
 	x, y = float64(cX), float64(cY)
 	return
 }
@@ -3198,7 +3737,8 @@ func (win *Window) SetCursorPos(x, y float64) {
 //
 // The pixels are 32-bit, little-endian, non-premultiplied RGBA, i.e. eight bits
 // per channel with the red channel first. They are arranged canonically as
-// packed sequential rows, starting from the top-left corner.
+// packed sequential rows, starting from the top-left corner. Missing pixels are
+// transparent.
 //
 // The cursor hotspot is specified in pixels, relative to the upper-left corner
 // of the cursor image. Like all other coordiate systems in GLFW, the X-axis
@@ -3206,11 +3746,32 @@ func (win *Window) SetCursorPos(x, y float64) {
 //
 // Returns the handle of the created cursor, or nil if an error occurred.
 //
-// Possible errors include NotInitialized and PlatformError.
+// Possible errors include NotInitialized, InvalidValue, OutOfMemory and
+// PlatformError. InvalidValue is emitted if the image is larger than 1 GiB.
 //
 // This function must only be called from the main thread.
 func (c *Context) CreateCursor(image *Image, xhot, yhot int) *Cursor {
-	return (*Cursor)(C.glfwCreateCursor(image.c(), C.int(xhot), C.int(yhot)))
+	cImage := cImages([]Image{*image})
+	if cImage == nil {
+		return nil
+	}
+	defer C.free(unsafe.Pointer(cImage))
+	return (*Cursor)(C.glfwCreateCursor(cImage, C.int(xhot), C.int(yhot)))
+}
+
+// CreateCursorErr is like Context.CreateCursor(), but returns the error that
+// caused the cursor creation to fail instead of nil.
+//
+// This function must only be called from the main thread.
+func (c *Context) CreateCursorErr(image *Image, xhot, yhot int) (*Cursor, error) {
+	var cursor *Cursor
+	err := catchError(func() {
+		cursor = c.CreateCursor(image, xhot, yhot)
+	})
+	if cursor == nil {
+		return nil, failedError(err, "cursor creation")
+	}
+	return cursor, nil
 }
 
 // CreateStandardCursor creates a cursor with a standard shape
@@ -3289,18 +3850,14 @@ func (win *Window) SetCursor(cursor *Cursor) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetKeyCallback(callback KeyCallback) KeyCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.KeyCallback
 	callbacks.KeyCallback = callback
 
 	if callback != nil {
 		C.goSetKeyCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveKeyCallback(win.c())
 	}
 
@@ -3308,12 +3865,17 @@ func (win *Window) SetKeyCallback(callback KeyCallback) KeyCallback {
 }
 
 //export _keyCallback
-func _keyCallback(cWin *C.GLFWwindow, cKey, cScancode, cAction, cMods C.int) {
+func _keyCallback(cWin *C.GLFWwindow, handle C.uintptr_t, cKey, cScancode, cAction, cMods C.int) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.KeyCallback != nil {
-		key, scancode, action, mods := Key(cKey), int(cScancode), Action(cAction), ModifierFlag(cMods)
-		callbacks.KeyCallback(win, key, scancode, action, mods)
+	key, scancode, action, mods := Key(cKey), int(cScancode), Action(cAction), ModifierFlag(cMods)
+	if callbacks := directCallbacks(handle); callbacks != nil {
+		if callbacks.KeyCallback != nil {
+			callbacks.KeyCallback(win, key, scancode, action, mods)
+		}
+		return
 	}
+	dispatchEventTo(windowStateOf(handle), KeyEvent{Window: win, Key: key, Scancode: scancode, Action: action, Mods: mods})
 }
 
 // SetCharCallback sets the character callback of win, which is called when a
@@ -3340,18 +3902,14 @@ func _keyCallback(cWin *C.GLFWwindow, cKey, cScancode, cAction, cMods C.int) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetCharCallback(callback CharCallback) CharCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.CharCallback
 	callbacks.CharCallback = callback
 
 	if callback != nil {
 		C.goSetCharCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveCharCallback(win.c())
 	}
 
@@ -3359,12 +3917,17 @@ func (win *Window) SetCharCallback(callback CharCallback) CharCallback {
 }
 
 //export _charCallback
-func _charCallback(cWin *C.GLFWwindow, cCodepoint C.uint) {
+func _charCallback(cWin *C.GLFWwindow, handle C.uintptr_t, cCodepoint C.uint) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.CharCallback != nil {
-		codepoint := rune(cCodepoint)
-		callbacks.CharCallback(win, codepoint)
+	codepoint := rune(cCodepoint)
+	if callbacks := directCallbacks(handle); callbacks != nil {
+		if callbacks.CharCallback != nil {
+			callbacks.CharCallback(win, codepoint)
+		}
+		return
 	}
+	dispatchEventTo(windowStateOf(handle), CharEvent{Window: win, Codepoint: codepoint})
 }
 
 // SetCharModsCallback sets the character with modifiers callback of win, which
@@ -3391,18 +3954,14 @@ func _charCallback(cWin *C.GLFWwindow, cCodepoint C.uint) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetCharModsCallback(callback CharModsCallback) CharModsCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.CharModsCallback
 	callbacks.CharModsCallback = callback
 
 	if callback != nil {
 		C.goSetCharModsCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveCharModsCallback(win.c())
 	}
 
@@ -3410,12 +3969,17 @@ func (win *Window) SetCharModsCallback(callback CharModsCallback) CharModsCallba
 }
 
 //export _charModsCallback
-func _charModsCallback(cWin *C.GLFWwindow, cCodepoint C.uint, cMods C.int) {
+func _charModsCallback(cWin *C.GLFWwindow, handle C.uintptr_t, cCodepoint C.uint, cMods C.int) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.CharModsCallback != nil {
-		codepoint, mods := rune(cCodepoint), ModifierFlag(cMods)
-		callbacks.CharModsCallback(win, codepoint, mods)
+	codepoint, mods := rune(cCodepoint), ModifierFlag(cMods)
+	if callbacks := directCallbacks(handle); callbacks != nil {
+		if callbacks.CharModsCallback != nil {
+			callbacks.CharModsCallback(win, codepoint, mods)
+		}
+		return
 	}
+	dispatchEventTo(windowStateOf(handle), CharModsEvent{Window: win, Codepoint: codepoint, Mods: mods})
 }
 
 // SetMouseButtonCallback sets the mouse button callback of win, which is called
@@ -3436,18 +4000,14 @@ func _charModsCallback(cWin *C.GLFWwindow, cCodepoint C.uint, cMods C.int) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetMouseButtonCallback(callback MouseButtonCallback) MouseButtonCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.MouseButtonCallback
 	callbacks.MouseButtonCallback = callback
 
 	if callback != nil {
 		C.goSetMouseButtonCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveMouseButtonCallback(win.c())
 	}
 
@@ -3455,12 +4015,17 @@ func (win *Window) SetMouseButtonCallback(callback MouseButtonCallback) MouseBut
 }
 
 //export _mouseButtonCallback
-func _mouseButtonCallback(cWin *C.GLFWwindow, cButton, cAction, cMods C.int) {
+func _mouseButtonCallback(cWin *C.GLFWwindow, handle C.uintptr_t, cButton, cAction, cMods C.int) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.MouseButtonCallback != nil {
-		button, action, mods := Button(cButton), Action(cAction), ModifierFlag(cMods)
-		callbacks.MouseButtonCallback(win, button, action, mods)
+	button, action, mods := Button(cButton), Action(cAction), ModifierFlag(cMods)
+	if callbacks := directCallbacks(handle); callbacks != nil {
+		if callbacks.MouseButtonCallback != nil {
+			callbacks.MouseButtonCallback(win, button, action, mods)
+		}
+		return
 	}
+	dispatchEventTo(windowStateOf(handle), MouseButtonEvent{Window: win, Button: button, Action: action, Mods: mods})
 }
 
 // SetCursorPosCallback sets the cursor position callback of win, which is
@@ -3477,18 +4042,14 @@ func _mouseButtonCallback(cWin *C.GLFWwindow, cButton, cAction, cMods C.int) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetCursorPosCallback(callback CursorPosCallback) CursorPosCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.CursorPosCallback
 	callbacks.CursorPosCallback = callback
 
 	if callback != nil {
 		C.goSetCursorPosCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveCursorPosCallback(win.c())
 	}
 
@@ -3496,12 +4057,17 @@ func (win *Window) SetCursorPosCallback(callback CursorPosCallback) CursorPosCal
 }
 
 //export _cursorPosCallback
-func _cursorPosCallback(cWin *C.GLFWwindow, cX, cY C.double) {
+func _cursorPosCallback(cWin *C.GLFWwindow, handle C.uintptr_t, cX, cY C.double) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.CursorPosCallback != nil {
-		x, y := float64(cX), float64(cY)
-		callbacks.CursorPosCallback(win, x, y)
+	x, y := float64(cX), float64(cY)
+	if callbacks := directCallbacks(handle); callbacks != nil {
+		if callbacks.CursorPosCallback != nil {
+			callbacks.CursorPosCallback(win, x, y)
+		}
+		return
 	}
+	dispatchEventTo(windowStateOf(handle), CursorPosEvent{Window: win, X: x, Y: y})
 }
 
 // SetCursorEnterCallback sets the cursor boundary crossing callback of win,
@@ -3517,18 +4083,14 @@ func _cursorPosCallback(cWin *C.GLFWwindow, cX, cY C.double) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetCursorEnterCallback(callback CursorEnterCallback) CursorEnterCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.CursorEnterCallback
 	callbacks.CursorEnterCallback = callback
 
 	if callback != nil {
 		C.goSetCursorEnterCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveCursorEnterCallback(win.c())
 	}
 
@@ -3536,12 +4098,17 @@ func (win *Window) SetCursorEnterCallback(callback CursorEnterCallback) CursorEn
 }
 
 //export _cursorEnterCallback
-func _cursorEnterCallback(cWin *C.GLFWwindow, cEntered C.int) {
+func _cursorEnterCallback(cWin *C.GLFWwindow, handle C.uintptr_t, cEntered C.int) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.CursorEnterCallback != nil {
-		entered := int(cEntered) == True
-		callbacks.CursorEnterCallback(win, entered)
+	entered := int(cEntered) == True
+	if callbacks := directCallbacks(handle); callbacks != nil {
+		if callbacks.CursorEnterCallback != nil {
+			callbacks.CursorEnterCallback(win, entered)
+		}
+		return
 	}
+	dispatchEventTo(windowStateOf(handle), CursorEnterEvent{Window: win, Entered: entered})
 }
 
 // SetScrollCallback sets the scroll callback of win, which is called when a
@@ -3560,18 +4127,14 @@ func _cursorEnterCallback(cWin *C.GLFWwindow, cEntered C.int) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetScrollCallback(callback ScrollCallback) ScrollCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.ScrollCallback
 	callbacks.ScrollCallback = callback
 
 	if callback != nil {
 		C.goSetScrollCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveScrollCallback(win.c())
 	}
 
@@ -3579,12 +4142,17 @@ func (win *Window) SetScrollCallback(callback ScrollCallback) ScrollCallback {
 }
 
 //export _scrollCallback
-func _scrollCallback(cWin *C.GLFWwindow, cXOffset, cYOffset C.double) {
+func _scrollCallback(cWin *C.GLFWwindow, handle C.uintptr_t, cXOffset, cYOffset C.double) {
+	defer recoverCallback()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.ScrollCallback != nil {
-		xOffset, yOffset := float64(cXOffset), float64(cYOffset)
-		callbacks.ScrollCallback(win, xOffset, yOffset)
+	xOffset, yOffset := float64(cXOffset), float64(cYOffset)
+	if callbacks := directCallbacks(handle); callbacks != nil {
+		if callbacks.ScrollCallback != nil {
+			callbacks.ScrollCallback(win, xOffset, yOffset)
+		}
+		return
 	}
+	dispatchEventTo(windowStateOf(handle), ScrollEvent{Window: win, XOffset: xOffset, YOffset: yOffset})
 }
 
 // SetDropCallback sets the file drop callback of win, which is called when one
@@ -3601,18 +4169,14 @@ func _scrollCallback(cWin *C.GLFWwindow, cXOffset, cYOffset C.double) {
 //
 // This function must only be called from the main thread.
 func (win *Window) SetDropCallback(callback DropCallback) DropCallback {
-	callbacks, exist := windowCallbacks[win]
-	if !exist {
-		callbacks = new(WindowCallbacks)
-		windowCallbacks[win] = callbacks
-	}
+	callbacks := win.callbacks()
 
 	previousCallback := callbacks.DropCallback
 	callbacks.DropCallback = callback
 
 	if callback != nil {
 		C.goSetDropCallback(win.c())
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveDropCallback(win.c())
 	}
 
@@ -3620,18 +4184,18 @@ func (win *Window) SetDropCallback(callback DropCallback) DropCallback {
 }
 
 //export _dropCallback
-func _dropCallback(cWin *C.GLFWwindow, cCount C.int, cPaths **C.char) {
+func _dropCallback(cWin *C.GLFWwindow, handle C.uintptr_t, cCount C.int, cPaths **C.char) {
+	defer recoverCallback()
+	flushEventBatch()
 	win := (*Window)(cWin)
-	if callbacks, exist := windowCallbacks[win]; exist && callbacks.DropCallback != nil {
-		count := int(cCount)
-		paths := make([]string, 0, count)
-		for i := 0; i < count; i++ {
-			offset := unsafe.Sizeof(*cPaths) * uintptr(i)
-			cPath := (*C.char)(unsafe.Pointer(uintptr(unsafe.Pointer(cPaths)) + offset))
-			paths = append(paths, C.GoString(cPath))
-		}
-		callbacks.DropCallback(win, paths)
+	count := int(cCount)
+	paths := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		offset := unsafe.Sizeof(*cPaths) * uintptr(i)
+		cPath := *(**C.char)(unsafe.Pointer(uintptr(unsafe.Pointer(cPaths)) + offset))
+		paths = append(paths, C.GoString(cPath))
 	}
+	dispatchEventTo(windowStateOf(handle), DropEvent{Window: win, Paths: paths})
 }
 
 // Present returns whether the specified joystick is present.
@@ -3643,6 +4207,9 @@ func _dropCallback(cWin *C.GLFWwindow, cCount C.int, cPaths **C.char) {
 //
 // This function must only be called from the main thread.
 func (j Joystick) Present() bool {
+	if _, exist := injectedJoysticks[j]; exist {
+		return true
+	}
 	return int(C.glfwJoystickPresent(C.int(j))) == True
 }
 
@@ -3657,6 +4224,10 @@ func (j Joystick) Present() bool {
 //
 // This function must only be called from the main thread.
 func (j Joystick) GetAxes() []float32 {
+	if js, exist := injectedJoysticks[j]; exist {
+		return append([]float32(nil), js.state.Axes[:]...)
+	}
+
 	var cCount C.int
 	cAxes := C.glfwGetJoystickAxes(C.int(j), &cCount)
 	if unsafe.Pointer(cAxes) != C.NULL {
@@ -3676,9 +4247,9 @@ func (j Joystick) GetAxes() []float32 {
 // joystick. Each element in the slice is either Press or Release.
 //
 // For backward compatibility with earlier versions that did not have
-// Context.GetJoystickHats(), the button array also includes all hats, each
+// Joystick.GetHats(), the button array also includes all hats, each
 // represented as four buttons. The hats are in the same order as returned by
-// Context.GetJoystickHats() and are in the order up, right, down and left. To
+// Joystick.GetHats() and are in the order up, right, down and left. To
 // disable these extra buttons, set the JoystickHatButtons init hint before
 // initialization.
 //
@@ -3690,6 +4261,10 @@ func (j Joystick) GetAxes() []float32 {
 //
 // This function must only be called from the main thread.
 func (j Joystick) GetButtons() []Action {
+	if js, exist := injectedJoysticks[j]; exist {
+		return append([]Action(nil), js.state.Buttons[:]...)
+	}
+
 	var cCount C.int
 	cActions := C.glfwGetJoystickButtons(C.int(j), &cCount)
 	if unsafe.Pointer(cActions) != C.NULL {
@@ -3705,6 +4280,45 @@ func (j Joystick) GetButtons() []Action {
 	return nil
 }
 
+// GetHats returns the state of all hats of the specified joystick. Each
+// element in the slice is one of the HatState values.
+//
+// The diagonal directions are bitwise combinations of the primary (up, right,
+// down and left) directions and you can test for these individually with the
+// HatState methods Up(), Right(), Down() and Left(), or by ANDing the state
+// with the corresponding direction.
+//
+//     if hats[2]&HatRight != 0 {
+//         // State of hat 2 could be right-up, right or right-down.
+//     }
+//
+// If the specified joystick is not present this function will return nil but
+// will not generate an error. This can be used instead of first calling
+// Joystick.Present().
+//
+// Possible errors include NotInitialized, InvalidEnum and PlatformError.
+//
+// This function must only be called from the main thread.
+func (j Joystick) GetHats() []HatState {
+	if _, exist := injectedJoysticks[j]; exist {
+		return []HatState{}
+	}
+
+	var cCount C.int
+	cHats := C.glfwGetJoystickHats(C.int(j), &cCount)
+	if unsafe.Pointer(cHats) != C.NULL {
+		count := int(cCount)
+		hats := make([]HatState, 0, count)
+		for i := 0; i < count; i++ {
+			offset := unsafe.Sizeof(*cHats) * uintptr(i)
+			cHat := (*C.uchar)(unsafe.Pointer(uintptr(unsafe.Pointer(cHats)) + offset))
+			hats = append(hats, HatState(*cHat))
+		}
+		return hats
+	}
+	return nil
+}
+
 // GetGUID returns the SDL comaptible GUID of the specified joystick.
 //
 // This function returns the SDL compatible GUID, as a UTF-8 encoded hexadecimal
@@ -3729,6 +4343,9 @@ func (j Joystick) GetButtons() []Action {
 //
 // This function must only be called from the main thread.
 func (j Joystick) GetGUID() string {
+	if js, exist := injectedJoysticks[j]; exist {
+		return js.guid
+	}
 	return C.GoString(C.glfwGetJoystickGUID(C.int(j)))
 }
 
@@ -3777,6 +4394,9 @@ func (j Joystick) GetUserPointer() unsafe.Pointer {
 //
 // This function must only be called from the main thread.
 func (j Joystick) IsGamepad() bool {
+	if _, exist := injectedJoysticks[j]; exist {
+		return true
+	}
 	return int(C.glfwJoystickIsGamepad(C.int(j))) == int(True)
 }
 
@@ -3791,6 +4411,9 @@ func (j Joystick) IsGamepad() bool {
 //
 // This function must only be called from the main thread.
 func (j Joystick) GetName() string {
+	if js, exist := injectedJoysticks[j]; exist {
+		return js.name
+	}
 	return C.GoString(C.glfwGetJoystickName(C.int(j)))
 }
 
@@ -3817,7 +4440,7 @@ func (c *Context) SetJoystickCallback(callback JoystickCallback) JoystickCallbac
 	joystickCallback = callback
 	if callback != nil {
 		C.goSetJoystickCallback()
-	} else {
+	} else if !eventsEnabled() {
 		C.goRemoveJoystickCallback()
 	}
 	return previousCallback
@@ -3825,10 +4448,10 @@ func (c *Context) SetJoystickCallback(callback JoystickCallback) JoystickCallbac
 
 //export _joystickCallback
 func _joystickCallback(cJoy, cEvent C.int) {
-	if joystickCallback != nil {
-		joy, event := Joystick(cJoy), ConnectionEvent(cEvent)
-		joystickCallback(joy, event)
-	}
+	defer recoverCallback()
+	flushEventBatch()
+	joy, event := Joystick(cJoy), ConnectionEvent(cEvent)
+	dispatchEvent(JoystickEvent{Joystick: joy, Event: event})
 }
 
 // UpdateGamepadMappings adds the specified SDL_GameControllerDB gamepad
@@ -3860,6 +4483,29 @@ func (c *Context) UpdateGamepadMappings(mappings string) bool {
 	return int(C.glfwUpdateGamepadMappings(cMappings)) == int(True)
 }
 
+// UpdateGamepadMappingsErr is like Context.UpdateGamepadMappings(), but returns
+// an error instead of false.
+//
+// GLFW skips the mappings it rejects and still reports success, so the update
+// only fails as a whole if the library is not initialized. Some rejected
+// mappings generate an InvalidValue error, in which case the error of the last
+// one is returned even though the other mappings were added, but mappings that
+// are too long or have an unknown platform are skipped silently. Use
+// Context.LoadGamepadMappings() to get a GamepadMappingError for each rejected
+// line instead.
+//
+// This function must only be called from the main thread.
+func (c *Context) UpdateGamepadMappingsErr(mappings string) error {
+	var ok bool
+	err := catchError(func() {
+		ok = c.UpdateGamepadMappings(mappings)
+	})
+	if !ok {
+		return failedError(err, "gamepad mapping update")
+	}
+	return err
+}
+
 // GetGamepadName returns the human-readable gamepad name for the specified
 // joystick.
 //
@@ -3873,6 +4519,9 @@ func (c *Context) UpdateGamepadMappings(mappings string) bool {
 //
 // This function must only be called from the main thread.
 func (j Joystick) GetGamepadName() string {
+	if js, exist := injectedJoysticks[j]; exist {
+		return js.name
+	}
 	return C.GoString(C.glfwGetGamepadName(C.int(j)))
 }
 
@@ -3893,24 +4542,33 @@ func (j Joystick) GetGamepadName() string {
 // Not all devices have all the buttons or axes provided by GamepadState.
 // Unavailable buttons and axes will always report Release and 0.0 respectively.
 //
+// While a recording is replayed, the recorded states are returned instead. See
+// Replayer.
+//
 // Possible errors include NotInitialized and InvalidEnum.
 //
 // This function must only be called from the main thread.
 func (j Joystick) GetGamepadState() (*GamepadState, bool) {
-	cState := new(C.GLFWgamepadstate)
-	cSuccess := C.glfwGetGamepadState(C.int(j), cState)
-	if int(cSuccess) == int(False) {
-		return nil, false
+	if state, ok, found := replayedGamepadState(j); found {
+		return state, ok
 	}
 
-	state := new(GamepadState)
-	for i, cButton := range cState.buttons {
-		state.Buttons[i] = Action(cButton)
-	}
-	for i, cAxis := range cState.axes {
-		state.Axes[i] = float32(cAxis)
+	var state *GamepadState
+	cState := new(C.GLFWgamepadstate)
+	if js, exist := injectedJoysticks[j]; exist {
+		state = new(GamepadState)
+		*state = js.state
+	} else if int(C.glfwGetGamepadState(C.int(j), cState)) == int(True) {
+		state = new(GamepadState)
+		for i, cButton := range cState.buttons {
+			state.Buttons[i] = Action(cButton)
+		}
+		for i, cAxis := range cState.axes {
+			state.Axes[i] = float32(cAxis)
+		}
 	}
-	return state, true
+
+	return state, state != nil
 }
 
 // SetClipboardString sets the system clipboard to str, a UTF-8 encoded string.
@@ -4256,7 +4914,7 @@ func (c *Context) GetRequiredInstanceExtensions() []string {
 		var i uint32
 		for i = 0; i < count; i++ {
 			offset := unsafe.Sizeof(*cExtensions) * uintptr(i)
-			cExtension := (*C.char)(unsafe.Pointer(uintptr(unsafe.Pointer(cExtensions)) + offset))
+			cExtension := *(**C.char)(unsafe.Pointer(uintptr(unsafe.Pointer(cExtensions)) + offset))
 			extensions = append(extensions, C.GoString(cExtension))
 		}
 
@@ -4264,3 +4922,145 @@ func (c *Context) GetRequiredInstanceExtensions() []string {
 	}
 	return nil
 }
+
+// VulkanError is returned by Vulkan related functions if the underlying Vulkan
+// call failed.
+type VulkanError struct {
+	// Result : The VkResult returned by the failed call.
+	Result int32
+}
+
+// Names of the VkResult error codes that GLFW may return.
+var vulkanResultNames = map[int32]string{
+	-1:          "VK_ERROR_OUT_OF_HOST_MEMORY",
+	-2:          "VK_ERROR_OUT_OF_DEVICE_MEMORY",
+	-3:          "VK_ERROR_INITIALIZATION_FAILED",
+	-7:          "VK_ERROR_EXTENSION_NOT_PRESENT",
+	-1000000000: "VK_ERROR_SURFACE_LOST_KHR",
+	-1000000001: "VK_ERROR_NATIVE_WINDOW_IN_USE_KHR",
+}
+
+func (err *VulkanError) Error() string {
+	if name, exist := vulkanResultNames[err.Result]; exist {
+		return fmt.Sprintf("glfw: Vulkan call failed with %s", name)
+	}
+	return fmt.Sprintf("glfw: Vulkan call failed with VkResult %d", err.Result)
+}
+
+// GetInstanceProcAddress returns the address of the specified Vulkan core or
+// extension function for instance, a VkInstance handle. If instance is zero it
+// can return any function exported from the Vulkan loader, including at least
+// the following functions:
+//
+// - vkEnumerateInstanceExtensionProperties
+//
+// - vkEnumerateInstanceLayerProperties
+//
+// - vkCreateInstance
+//
+// - vkGetInstanceProcAddr
+//
+// If Vulkan is not available on the machine, this function returns nil and
+// generates an APIUnavailable error. Call Context.VulkanSupported() to check
+// whether Vulkan is at least minimally available.
+//
+// This function is equivalent to calling vkGetInstanceProcAddr with a
+// platform-specific query of the Vulkan loader as a fallback.
+//
+// Returns the address of the function, or nil if an error occurred.
+//
+// Possible errors include NotInitialized and APIUnavailable.
+//
+// The returned function pointer is valid until the library is terminated.
+//
+// This function may be called from any thread.
+func (c *Context) GetInstanceProcAddress(instance uintptr, procName string) unsafe.Pointer {
+	cProcName := C.CString(procName)
+	defer C.free(unsafe.Pointer(cProcName))
+	return C.goGetInstanceProcAddress(C.uintptr_t(instance), cProcName)
+}
+
+// GetPhysicalDevicePresentationSupport returns whether the specified queue
+// family of device, a VkPhysicalDevice handle belonging to instance, supports
+// presentation to the platform GLFW was built for.
+//
+// If Vulkan or the required window surface creation instance extensions are
+// not available on the machine, or if instance was not created with the
+// required extensions, this function returns false and generates an
+// APIUnavailable error. Call Context.VulkanSupported() to check whether Vulkan
+// is at least minimally available and Context.GetRequiredInstanceExtensions()
+// to check what instance extensions are required.
+//
+// Possible errors include NotInitialized, APIUnavailable and PlatformError.
+//
+// On macOS, this function currently always returns true, as the
+// VK_MVK_macos_surface extension does not provide a
+// vkGetPhysicalDevice*PresentationSupport type function.
+//
+// instance and device must not be zero, otherwise this function returns false.
+//
+// This function may be called from any thread. For synchronization details of
+// Vulkan objects, see the Vulkan specification.
+func (c *Context) GetPhysicalDevicePresentationSupport(instance, device uintptr, queueFamily uint32) bool {
+	if instance == 0 || device == 0 {
+		return false
+	}
+	return int(C.goGetPhysicalDevicePresentationSupport(C.uintptr_t(instance), C.uintptr_t(device), C.uint32_t(queueFamily))) == True
+}
+
+// CreateWindowSurface creates a Vulkan surface for win in instance, a
+// VkInstance handle, and returns the VkSurfaceKHR handle of the surface.
+// allocator is the address of the VkAllocationCallbacks to use, or zero to use
+// the default allocator.
+//
+// If the Vulkan loader or at least one minimally functional ICD were not
+// found, this function returns a VulkanError with
+// VK_ERROR_INITIALIZATION_FAILED and generates an APIUnavailable error. Call
+// Context.VulkanSupported() to check whether Vulkan is at least minimally
+// available.
+//
+// If the required window surface creation instance extensions are not
+// available or if instance was not created with these extensions enabled, this
+// function returns a VulkanError with VK_ERROR_EXTENSION_NOT_PRESENT and
+// generates an APIUnavailable error. Call
+// Context.GetRequiredInstanceExtensions() to check what instance extensions
+// are required.
+//
+// The window surface cannot be shared with another API so win must have been
+// created with the ClientAPI hint set to NoAPI, otherwise it generates an
+// InvalidValue error and returns a VulkanError with
+// VK_ERROR_NATIVE_WINDOW_IN_USE_KHR.
+//
+// The window surface must be destroyed before instance. It is the
+// responsibility of the caller to destroy the window surface. GLFW does not
+// destroy it for you. Call vkDestroySurfaceKHR to destroy the surface.
+//
+// Possible errors include NotInitialized, APIUnavailable, PlatformError and
+// InvalidValue.
+//
+// instance must not be zero, otherwise this function returns an error without
+// calling GLFW.
+//
+// VkSurfaceKHR is a 64-bit handle on all platforms, including 32-bit ones where
+// it is not a pointer, so it is returned as a uint64.
+//
+// On macOS, this function currently only supports the VK_MVK_macos_surface
+// extension from MoltenVK.
+//
+// On macOS, this function creates and sets a CAMetalLayer instance for the
+// window content view, which is required for MoltenVK to function.
+//
+// This function may be called from any thread. For synchronization details of
+// Vulkan objects, see the Vulkan specification.
+func (win *Window) CreateWindowSurface(instance uintptr, allocator uintptr) (uint64, error) {
+	if instance == 0 {
+		return 0, fmt.Errorf("glfw: CreateWindowSurface: instance is zero")
+	}
+
+	var cSurface C.VkSurfaceKHR
+	result := int32(C.goCreateWindowSurface(C.uintptr_t(instance), win.c(), C.uintptr_t(allocator), &cSurface))
+	if result != 0 {
+		return 0, &VulkanError{Result: result}
+	}
+	return uint64(cSurface), nil
+}
@@ -0,0 +1,288 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package glfw
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// initTest initializes the library for a test. The test must call
+// Context.Terminate() when done.
+func initTest(t testing.TB) *Context {
+	ctx, err := InitErr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+// createTestWindow creates a window with no client API, as the null platform
+// has no context.
+func createTestWindow(t testing.TB, ctx *Context) *Window {
+	ctx.WindowHint(ClientAPI, NoAPI)
+	win, err := ctx.CreateWindowErr(640, 480, "test", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return win
+}
+
+// testGUID is the GUID of the synthetic gamepads connected by the tests, that
+// of a wired Xbox 360 controller on Linux.
+const testGUID = "030000005e0400008e02000014010000"
+
+// testImages returns the usual sizes of window icons.
+func testImages() []Image {
+	var images []Image
+	for _, size := range []int{16, 32, 48, 256} {
+		images = append(images, Image{Width: size, Height: size, Pixels: make([]uint8, 4*size*size)})
+	}
+	return images
+}
+
+// testGammaRamp returns a gamma ramp of size entries per channel.
+func testGammaRamp(size int) *GammaRamp {
+	ramp := &GammaRamp{Red: make([]uint16, size), Green: make([]uint16, size), Blue: make([]uint16, size)}
+	for i := 0; i < size; i++ {
+		ramp.Red[i], ramp.Green[i], ramp.Blue[i] = uint16(i), uint16(2*i), uint16(3*i)
+	}
+	return ramp
+}
+
+func TestSetIconEmpty(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	defer win.Destroy()
+
+	win.SetIcon(nil)
+	win.SetIcon([]Image{})
+	win.SetIcon([]Image{{Width: 16, Height: 16}})
+	win.SetIcon([]Image{{Width: 16, Height: 16, Pixels: make([]uint8, 10)}, {}})
+}
+
+func TestCreateCursorEmpty(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+
+	cursor, err := ctx.CreateCursorErr(&Image{Width: 16, Height: 16}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cursor.Destroy()
+}
+
+func TestPackPixels(t *testing.T) {
+	for _, pixels := range [][]uint8{nil, {1, 2, 3}, {1, 2, 3, 4, 5, 6, 7, 8, 9}} {
+		dst := []uint8{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+		packPixels(dst, pixels)
+		for i, pixel := range dst {
+			want := uint8(0)
+			if i < len(pixels) {
+				want = pixels[i]
+			}
+			if pixel != want {
+				t.Errorf("packPixels(%v) = %v", pixels, dst)
+				break
+			}
+		}
+	}
+}
+
+func TestGammaRampMismatchedLengths(t *testing.T) {
+	ramp := &GammaRamp{Red: []uint16{1, 2, 3, 4}, Green: []uint16{5, 6, 7}, Blue: []uint16{8, 9, 10, 11, 12}}
+	size := ramp.size()
+	if size != 3 {
+		t.Fatalf("size() = %d, want 3", size)
+	}
+	channels := make([]uint16, 3*size)
+	ramp.pack(channels)
+	want := []uint16{1, 2, 3, 5, 6, 7, 8, 9, 10}
+	for i := range want {
+		if channels[i] != want[i] {
+			t.Fatalf("pack() = %v, want %v", channels, want)
+		}
+	}
+	if size := (&GammaRamp{Red: []uint16{1}, Green: []uint16{2}}).size(); size != 0 {
+		t.Errorf("size() of a ramp with an empty channel = %d, want 0", size)
+	}
+}
+
+func TestImagesTooLarge(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	defer win.Destroy()
+
+	huge := Image{Width: 1 << 16, Height: 1 << 16}
+	if _, err := ctx.CreateCursorErr(&huge, 0, 0); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("CreateCursorErr() of a huge image = %v, want InvalidValue", err)
+	}
+	// The images are too large together, but not on their own.
+	large := Image{Width: 1 << 13, Height: 1 << 13}
+	err := catchError(func() {
+		win.SetIcon([]Image{large, large, large, large, large})
+	})
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("SetIcon() of images too large together = %v, want InvalidValue", err)
+	}
+}
+
+// The benchmarks measure the bulk packing of the pixels and gamma ramps, and
+// the calls of SetIcon and CreateCursor on the null platform. SetGammaRamp
+// cannot be called, as the null platform has no monitors.
+
+func BenchmarkSetIcon(b *testing.B) {
+	images := testImages()
+	b.Run("Pack", func(b *testing.B) {
+		b.ReportAllocs()
+		dst := make([]uint8, len(images[len(images)-1].Pixels))
+		for i := 0; i < b.N; i++ {
+			for _, image := range images {
+				packPixels(dst[:image.size()], image.Pixels)
+			}
+		}
+	})
+	b.Run("Call", func(b *testing.B) {
+		ctx := initTest(b)
+		defer ctx.Terminate()
+		win := createTestWindow(b, ctx)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			win.SetIcon(images)
+		}
+	})
+}
+
+func BenchmarkCreateCursor(b *testing.B) {
+	image := testImages()[1]
+	b.Run("Pack", func(b *testing.B) {
+		b.ReportAllocs()
+		dst := make([]uint8, image.size())
+		for i := 0; i < b.N; i++ {
+			packPixels(dst, image.Pixels)
+		}
+	})
+	b.Run("Call", func(b *testing.B) {
+		ctx := initTest(b)
+		defer ctx.Terminate()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			ctx.CreateCursor(&image, 0, 0).Destroy()
+		}
+	})
+}
+
+func BenchmarkGammaRampPack(b *testing.B) {
+	ramp := testGammaRamp(256)
+	channels := make([]uint16, 3*ramp.size())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ramp.pack(channels)
+	}
+}
+
+func TestCreateWindowSurfaceNoInstance(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	defer win.Destroy()
+
+	surface, err := win.CreateWindowSurface(0, 0)
+	if err == nil || surface != 0 {
+		t.Fatalf("CreateWindowSurface(0, 0) = %d, %v, want an error", surface, err)
+	}
+	if _, ok := err.(*VulkanError); ok {
+		t.Errorf("CreateWindowSurface(0, 0) returned a VulkanError: %v", err)
+	}
+}
+
+func TestHatState(t *testing.T) {
+	const d = math.Sqrt2 / 2
+	tests := []struct {
+		state                 HatState
+		up, down, left, right bool
+		x, y                  float32
+	}{
+		{HatCentered, false, false, false, false, 0, 0},
+		{HatUp, true, false, false, false, 0, -1},
+		{HatRight, false, false, false, true, 1, 0},
+		{HatDown, false, true, false, false, 0, 1},
+		{HatLeft, false, false, true, false, -1, 0},
+		{HatRightUp, true, false, false, true, d, -d},
+		{HatRightDown, false, true, false, true, d, d},
+		{HatLeftUp, true, false, true, false, -d, -d},
+		{HatLeftDown, false, true, true, false, -d, d},
+		// Opposite directions cancel out.
+		{HatLeft | HatRight, false, false, true, true, 0, 0},
+		{HatUp | HatDown | HatRight, true, true, false, true, 1, 0},
+	}
+	for _, test := range tests {
+		state := test.state
+		if state.Up() != test.up || state.Down() != test.down || state.Left() != test.left || state.Right() != test.right {
+			t.Errorf("HatState(%d): Up, Down, Left, Right = %v, %v, %v, %v, want %v, %v, %v, %v", state,
+				state.Up(), state.Down(), state.Left(), state.Right(), test.up, test.down, test.left, test.right)
+		}
+		if x, y := state.Direction(); x != test.x || y != test.y {
+			t.Errorf("HatState(%d).Direction() = %v, %v, want %v, %v", state, x, y, test.x, test.y)
+		}
+	}
+}
+
+func TestGetHats(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+
+	if hats := Joystick16.GetHats(); hats != nil {
+		t.Errorf("GetHats() of an absent joystick = %v, want nil", hats)
+	}
+	Joystick1.InjectConnect("pad", testGUID)
+	defer Joystick1.InjectDisconnect()
+	if hats := Joystick1.GetHats(); hats == nil || len(hats) != 0 {
+		t.Errorf("GetHats() of an injected joystick = %v, want no hats", hats)
+	}
+}
+
+func TestErrorSentinels(t *testing.T) {
+	ctx := initTest(t)
+
+	_, err := ctx.CreateWindowErr(0, 480, "test", nil, nil)
+	var glfwErr *GLFWError
+	if !errors.As(err, &glfwErr) || glfwErr.Code != InvalidValue || glfwErr.Description == "" {
+		t.Errorf("CreateWindowErr(0, 480) = %#v, want a described InvalidValue GLFWError", err)
+	}
+	if !errors.Is(err, ErrInvalidValue) || errors.Is(err, ErrInvalidEnum) {
+		t.Errorf("errors.Is(%v) does not match the ErrInvalidValue sentinel only", err)
+	}
+
+	ctx.Terminate()
+	err = ctx.UpdateGamepadMappingsErr(gamepadMappingLine)
+	if !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("UpdateGamepadMappingsErr() after Terminate() = %v, want ErrNotInitialized", err)
+	}
+}
+
+// gamepadMappingLine is a valid gamepad mapping for any platform.
+const gamepadMappingLine = "03000000000000000000000000000000,Test Pad,a:b0,b:b1,x:b2,y:b3,"
+
+func TestUpdateGamepadMappingsErr(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+
+	if err := ctx.UpdateGamepadMappingsErr(gamepadMappingLine); err != nil {
+		t.Errorf("UpdateGamepadMappingsErr() of a valid mapping = %v", err)
+	}
+	err := ctx.UpdateGamepadMappingsErr("0123,Short GUID,a:b0,\n" + gamepadMappingLine)
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("UpdateGamepadMappingsErr() with a rejected mapping = %v, want ErrInvalidValue", err)
+	}
+}
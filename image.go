@@ -0,0 +1,86 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package glfw
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// NewImage returns an Image with the pixels of img converted to tightly packed,
+// non-premultiplied RGBA, the format expected by Window.SetIcon() and
+// Context.CreateCursor().
+//
+// Any image.Image is accepted. Premultiplied images such as *image.RGBA are
+// converted to non-premultiplied alpha, and other color models such as
+// *image.Paletted, *image.Gray or *image.YCbCr are converted to RGBA.
+func NewImage(img image.Image) *Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	pixels := make([]uint8, 4*width*height)
+
+	switch src := img.(type) {
+	case *image.NRGBA:
+		for y := 0; y < height; y++ {
+			i := src.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+			copy(pixels[4*width*y:4*width*(y+1)], src.Pix[i:i+4*width])
+		}
+	case *image.Paletted:
+		palette := make([]color.NRGBA, len(src.Palette))
+		for i, c := range src.Palette {
+			palette[i] = color.NRGBAModel.Convert(c).(color.NRGBA)
+		}
+		for y := 0; y < height; y++ {
+			i := src.PixOffset(bounds.Min.X, bounds.Min.Y+y)
+			for x, index := range src.Pix[i : i+width] {
+				var c color.NRGBA
+				if int(index) < len(palette) {
+					c = palette[index]
+				}
+				j := 4 * (width*y + x)
+				pixels[j], pixels[j+1], pixels[j+2], pixels[j+3] = c.R, c.G, c.B, c.A
+			}
+		}
+	default:
+		dst := &image.NRGBA{Pix: pixels, Stride: 4 * width, Rect: image.Rect(0, 0, width, height)}
+		draw.Draw(dst, dst.Rect, img, bounds.Min, draw.Src)
+	}
+
+	return &Image{Width: width, Height: height, Pixels: pixels}
+}
+
+// ToImage returns a copy of img as an *image.NRGBA, whose bounds start at
+// (0, 0). The pixels of img must be tightly packed, non-premultiplied RGBA.
+func (img *Image) ToImage() *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, img.Width, img.Height))
+	copy(dst.Pix, img.Pixels)
+	return dst
+}
+
+// SetIconImages is like Window.SetIcon(), but takes the candidate images as
+// image.Image, converted with NewImage(). If no images are specified, win
+// reverts to its default icon.
+//
+// This function must only be called from the main thread.
+func (win *Window) SetIconImages(images ...image.Image) {
+	icons := make([]Image, 0, len(images))
+	for _, img := range images {
+		icons = append(icons, *NewImage(img))
+	}
+	win.SetIcon(icons)
+}
+
+// CreateCursorImage is like Context.CreateCursor(), but takes the cursor image
+// as an image.Image, converted with NewImage(). hotspot is in the coordinate
+// space of img, like the arguments of img.At(), so a hotspot at
+// img.Bounds().Min is the top-left corner of the cursor.
+//
+// This function must only be called from the main thread.
+func (c *Context) CreateCursorImage(img image.Image, hotspot image.Point) *Cursor {
+	hotspot = hotspot.Sub(img.Bounds().Min)
+	return c.CreateCursor(NewImage(img), hotspot.X, hotspot.Y)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package glfw
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestNewImageNRGBA(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(-1, -1, 3, 3))
+	for y := -1; y < 3; y++ {
+		for x := -1; x < 3; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{uint8(x + 1), uint8(y + 1), 0x80, 0x40})
+		}
+	}
+	// A sub-image has a non-zero Min and a stride wider than its rows.
+	sub := src.SubImage(image.Rect(1, 0, 3, 2)).(*image.NRGBA)
+	img := NewImage(sub)
+	want := []uint8{
+		2, 1, 0x80, 0x40, 3, 1, 0x80, 0x40,
+		2, 2, 0x80, 0x40, 3, 2, 0x80, 0x40,
+	}
+	if img.Width != 2 || img.Height != 2 || !bytes.Equal(img.Pixels, want) {
+		t.Errorf("NewImage() = %dx%d %v, want 2x2 %v", img.Width, img.Height, img.Pixels, want)
+	}
+}
+
+func TestNewImagePaletted(t *testing.T) {
+	palette := color.Palette{
+		color.NRGBA{0xff, 0, 0, 0xff},
+		color.RGBA{0x40, 0x20, 0, 0x80},
+	}
+	src := image.NewPaletted(image.Rect(5, 5, 8, 6), palette)
+	src.Pix = []uint8{0, 1, 7}
+	img := NewImage(src)
+	want := []uint8{
+		0xff, 0, 0, 0xff,
+		0x7f, 0x3f, 0, 0x80,
+		// An index out of the palette is transparent.
+		0, 0, 0, 0,
+	}
+	if img.Width != 3 || img.Height != 1 || !bytes.Equal(img.Pixels, want) {
+		t.Errorf("NewImage() = %dx%d %v, want 3x1 %v", img.Width, img.Height, img.Pixels, want)
+	}
+}
+
+func TestNewImagePremultiplied(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 3, 1))
+	src.SetRGBA(0, 0, color.RGBA{0x40, 0x20, 0, 0x80})
+	src.SetRGBA(1, 0, color.RGBA{0xff, 0xff, 0xff, 0xff})
+	src.SetRGBA(2, 0, color.RGBA{})
+	img := NewImage(src)
+	want := []uint8{
+		0x7f, 0x3f, 0, 0x80,
+		0xff, 0xff, 0xff, 0xff,
+		0, 0, 0, 0,
+	}
+	if !bytes.Equal(img.Pixels, want) {
+		t.Errorf("NewImage() = %v, want the straight alpha %v", img.Pixels, want)
+	}
+
+	gray := image.NewGray(image.Rect(2, 2, 3, 3))
+	gray.SetGray(2, 2, color.Gray{0x30})
+	if img := NewImage(gray); !bytes.Equal(img.Pixels, []uint8{0x30, 0x30, 0x30, 0xff}) {
+		t.Errorf("NewImage() of a gray image = %v", img.Pixels)
+	}
+}
+
+func TestToImage(t *testing.T) {
+	img := &Image{Width: 2, Height: 1, Pixels: []uint8{1, 2, 3, 4, 5, 6, 7, 8}}
+	dst := img.ToImage()
+	if dst.Rect != image.Rect(0, 0, 2, 1) || !bytes.Equal(dst.Pix, img.Pixels) {
+		t.Errorf("ToImage() = %v %v, want the pixels of the image", dst.Rect, dst.Pix)
+	}
+	dst.Pix[0] = 0xff
+	if img.Pixels[0] != 1 {
+		t.Error("ToImage() shares the pixels of the image")
+	}
+	if back := NewImage(dst); !bytes.Equal(back.Pixels, dst.Pix) {
+		t.Errorf("NewImage(ToImage()) = %v, want %v", back.Pixels, dst.Pix)
+	}
+}
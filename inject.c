@@ -0,0 +1,47 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// The C side of input injection. See inject.go.
+
+#include "glfw/src/internal.h"
+#include "inject.h"
+
+void goInputKey(GLFWwindow* window, int key, int scancode, int action, int mods) {
+	_glfwInputKey((_GLFWwindow*) window, key, scancode, action, mods);
+}
+
+void goInputChar(GLFWwindow* window, unsigned int codepoint, int mods, int plain) {
+	_glfwInputChar((_GLFWwindow*) window, codepoint, mods, plain);
+}
+
+void goInputScroll(GLFWwindow* window, double xoffset, double yoffset) {
+	_glfwInputScroll((_GLFWwindow*) window, xoffset, yoffset);
+}
+
+void goInputMouseClick(GLFWwindow* window, int button, int action, int mods) {
+	_glfwInputMouseClick((_GLFWwindow*) window, button, action, mods);
+}
+
+void goInputCursorPos(GLFWwindow* window, double xpos, double ypos) {
+	_glfwInputCursorPos((_GLFWwindow*) window, xpos, ypos);
+}
+
+void goInputCursorEnter(GLFWwindow* window, int entered) {
+	_glfwInputCursorEnter((_GLFWwindow*) window, entered);
+}
+
+void goInputDrop(GLFWwindow* window, int count, const char** names) {
+	_glfwInputDrop((_GLFWwindow*) window, count, names);
+}
+
+void goGetVirtualCursorPos(GLFWwindow* handle, double* xpos, double* ypos) {
+	_GLFWwindow* window = (_GLFWwindow*) handle;
+	*xpos = window->virtualCursorPosX;
+	*ypos = window->virtualCursorPosY;
+}
+
+void goInputError(int code, const char* description) {
+	_glfwInputError(code, "%s", description);
+}
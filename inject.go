@@ -0,0 +1,162 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package glfw
+
+/*
+#include <stdlib.h>
+
+#include "inject.h"
+*/
+import "C"
+import "unsafe"
+
+// InjectKey injects a synthetic key event into win, as if key was pressed,
+// repeated or released. The key state of win is updated, so Window.GetKey()
+// reflects the injected event, and the key callback is called.
+//
+// As with real input, injecting Press for a key that is already pressed is
+// reported as Repeat, and injecting Release for a released key is ignored.
+//
+// This function must only be called from the main thread.
+func (win *Window) InjectKey(key Key, scancode int, action Action, mods ModifierFlag) {
+	C.goInputKey(win.c(), C.int(key), C.int(scancode), C.int(action), C.int(mods))
+}
+
+// InjectChar injects a synthetic Unicode character input into win. The
+// character mods callback is called and, unless mods contains ModControl or
+// ModAlt, the character callback is called too.
+//
+// Control characters are ignored.
+//
+// This function must only be called from the main thread.
+func (win *Window) InjectChar(codepoint rune, mods ModifierFlag) {
+	plain := C.int(False)
+	if mods&(ModControl|ModAlt) == 0 {
+		plain = C.int(True)
+	}
+	C.goInputChar(win.c(), C.uint(codepoint), C.int(mods), plain)
+}
+
+// InjectMouseButton injects a synthetic mouse button event into win. The mouse
+// button state of win is updated, so Window.GetMouseButton() reflects the
+// injected event, and the mouse button callback is called.
+//
+// This function must only be called from the main thread.
+func (win *Window) InjectMouseButton(button Button, action Action, mods ModifierFlag) {
+	C.goInputMouseClick(win.c(), C.int(button), C.int(action), C.int(mods))
+}
+
+// InjectCursorPos injects a synthetic cursor movement to x and y, relative to
+// the upper-left corner of the content area of win, and calls the cursor
+// position callback if the position changed.
+//
+// Window.GetCursorPos() reflects the injected position when the cursor is
+// disabled, and on the null platform, which has no system cursor. On other
+// platforms, it keeps reporting the position of the system cursor.
+//
+// This function must only be called from the main thread.
+func (win *Window) InjectCursorPos(x, y float64) {
+	C.goInputCursorPos(win.c(), C.double(x), C.double(y))
+}
+
+// InjectCursorEnter injects a synthetic cursor enter or leave event into win.
+//
+// This function must only be called from the main thread.
+func (win *Window) InjectCursorEnter(entered bool) {
+	cEntered := C.int(False)
+	if entered {
+		cEntered = C.int(True)
+	}
+	C.goInputCursorEnter(win.c(), cEntered)
+}
+
+// InjectScroll injects a synthetic scroll event into win.
+//
+// This function must only be called from the main thread.
+func (win *Window) InjectScroll(xOffset, yOffset float64) {
+	C.goInputScroll(win.c(), C.double(xOffset), C.double(yOffset))
+}
+
+// InjectDrop injects a synthetic drop of paths onto win.
+//
+// This function must only be called from the main thread.
+func (win *Window) InjectDrop(paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+
+	cPaths := (**C.char)(C.malloc(C.size_t(unsafe.Sizeof((*C.char)(nil)) * uintptr(len(paths)))))
+	defer C.free(unsafe.Pointer(cPaths))
+	for i, path := range paths {
+		offset := unsafe.Sizeof(*cPaths) * uintptr(i)
+		cPath := (**C.char)(unsafe.Pointer(uintptr(unsafe.Pointer(cPaths)) + offset))
+		*cPath = C.CString(path)
+		defer C.free(unsafe.Pointer(*cPath))
+	}
+	C.goInputDrop(win.c(), C.int(len(paths)), cPaths)
+}
+
+// injectedJoystick is a synthetic gamepad connected with
+// Joystick.InjectConnect().
+type injectedJoystick struct {
+	name  string
+	guid  string
+	state GamepadState
+}
+
+// injectedJoysticks are the connected synthetic gamepads.
+var injectedJoysticks = make(map[Joystick]*injectedJoystick)
+
+// InjectConnect connects a synthetic gamepad named name as j, and delivers a
+// JoystickEvent with Connected to the joystick callback and listeners.
+//
+// guid is the SDL compatible GUID returned by Joystick.GetGUID(), e.g. the
+// GUID of a real gamepad to test a GamepadProfile registered for it. It is
+// usually 32 hexadecimal digits, but it is returned as is.
+//
+// Until it is disconnected with Joystick.InjectDisconnect(), the synthetic
+// gamepad replaces any real joystick with the same ID. It is reported as
+// present and as a gamepad, its axes and buttons are those of its gamepad
+// state, and it has no hats. Its gamepad state is all released and centered
+// until set with Joystick.InjectGamepadState().
+//
+// This function must only be called from the main thread.
+func (j Joystick) InjectConnect(name, guid string) {
+	injectedJoysticks[j] = &injectedJoystick{name: name, guid: guid}
+	flushEventBatch()
+	dispatchEvent(JoystickEvent{Joystick: j, Event: Connected})
+}
+
+// InjectDisconnect disconnects the synthetic gamepad j, and delivers a
+// JoystickEvent with Disconnected to the joystick callback and listeners. Does
+// nothing if j is not a synthetic gamepad.
+//
+// This function must only be called from the main thread.
+func (j Joystick) InjectDisconnect() {
+	if _, exist := injectedJoysticks[j]; !exist {
+		return
+	}
+	delete(injectedJoysticks, j)
+	flushEventBatch()
+	dispatchEvent(JoystickEvent{Joystick: j, Event: Disconnected})
+}
+
+// InjectGamepadState sets the gamepad state of the synthetic gamepad j, as
+// returned by Joystick.GetGamepadState(), or resets it to all released and
+// centered if state is nil. Does nothing if j is not a synthetic gamepad.
+//
+// This function must only be called from the main thread.
+func (j Joystick) InjectGamepadState(state *GamepadState) {
+	js, exist := injectedJoysticks[j]
+	if !exist {
+		return
+	}
+	if state == nil {
+		js.state = GamepadState{}
+	} else {
+		js.state = *state
+	}
+}
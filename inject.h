@@ -0,0 +1,33 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+#ifndef GO_INJECT_H
+#define GO_INJECT_H
+
+#include "glfw/include/GLFW/glfw3.h"
+
+// These call GLFW's internal event input functions, which update the input
+// state of a window and call its callbacks, like the platform code does for
+// real input. They are defined in inject.c, which includes internal.h for the
+// prototypes of the internal functions and the _GLFWwindow struct.
+
+void goInputKey(GLFWwindow* window, int key, int scancode, int action, int mods);
+void goInputChar(GLFWwindow* window, unsigned int codepoint, int mods, int plain);
+void goInputScroll(GLFWwindow* window, double xoffset, double yoffset);
+void goInputMouseClick(GLFWwindow* window, int button, int action, int mods);
+void goInputCursorPos(GLFWwindow* window, double xpos, double ypos);
+void goInputCursorEnter(GLFWwindow* window, int entered);
+void goInputDrop(GLFWwindow* window, int count, const char** names);
+
+// goGetVirtualCursorPos returns the cursor position GLFW keeps for window,
+// which is the last position input or set while the cursor is disabled.
+void goGetVirtualCursorPos(GLFWwindow* window, double* xpos, double* ypos);
+
+// goInputError reports an error of the Go side through GLFW, so that it is
+// returned by glfwGetError() and passed to the error callback like the errors
+// of GLFW itself.
+void goInputError(int code, const char* description);
+
+#endif
@@ -0,0 +1,148 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package glfw
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInjectKey(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	defer win.Destroy()
+
+	var actions []Action
+	win.SetKeyCallback(func(win *Window, key Key, scancode int, action Action, mods ModifierFlag) {
+		if key != KeyA || scancode != 30 || mods != ModShift {
+			t.Errorf("key callback called with %v, %d, %v", key, scancode, mods)
+		}
+		actions = append(actions, action)
+	})
+	win.InjectKey(KeyA, 30, Press, ModShift)
+	if win.GetKey(KeyA) != Press {
+		t.Error("GetKey() after an injected press = Release")
+	}
+	win.InjectKey(KeyA, 30, Press, ModShift)
+	win.InjectKey(KeyA, 30, Release, ModShift)
+	win.InjectKey(KeyA, 30, Release, ModShift)
+	if win.GetKey(KeyA) != Release {
+		t.Error("GetKey() after an injected release = Press")
+	}
+	if want := []Action{Press, Repeat, Release}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("key callback called with %v, want %v", actions, want)
+	}
+}
+
+func TestInjectChar(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	defer win.Destroy()
+
+	var chars, charMods []rune
+	win.SetCharCallback(func(win *Window, char rune) {
+		chars = append(chars, char)
+	})
+	win.SetCharModsCallback(func(win *Window, char rune, mods ModifierFlag) {
+		charMods = append(charMods, char)
+	})
+	win.InjectChar('a', 0)
+	win.InjectChar('é', ModShift)
+	win.InjectChar('b', ModControl)
+	win.InjectChar('\n', 0)
+	if want := []rune{'a', 'é'}; !reflect.DeepEqual(chars, want) {
+		t.Errorf("char callback called with %q, want %q", chars, want)
+	}
+	if want := []rune{'a', 'é', 'b'}; !reflect.DeepEqual(charMods, want) {
+		t.Errorf("char mods callback called with %q, want %q", charMods, want)
+	}
+}
+
+func TestInjectMouse(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	defer win.Destroy()
+	queue := ctx.Events()
+
+	win.InjectCursorEnter(true)
+	win.InjectCursorPos(10, 20)
+	win.InjectCursorPos(10, 20)
+	win.InjectMouseButton(MouseButtonLeft, Press, 0)
+	if win.GetMouseButton(MouseButtonLeft) != Press {
+		t.Error("GetMouseButton() after an injected press = Release")
+	}
+	if x, y := win.GetCursorPos(); x != 10 || y != 20 {
+		t.Errorf("GetCursorPos() = %v, %v, want the injected 10, 20", x, y)
+	}
+	win.InjectScroll(0, -1)
+	win.InjectDrop(nil)
+	win.InjectDrop([]string{"a.txt", "b.txt"})
+
+	want := []Event{
+		CursorEnterEvent{Window: win, Entered: true},
+		CursorPosEvent{Window: win, X: 10, Y: 20},
+		MouseButtonEvent{Window: win, Button: MouseButtonLeft, Action: Press},
+		ScrollEvent{Window: win, YOffset: -1},
+		DropEvent{Window: win, Paths: []string{"a.txt", "b.txt"}},
+	}
+	if events := queue.Drain(); !reflect.DeepEqual(events, want) {
+		t.Errorf("injected events = %v, want %v", events, want)
+	}
+}
+
+func TestInjectGamepad(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+
+	var events []ConnectionEvent
+	ctx.SetJoystickCallback(func(j Joystick, event ConnectionEvent) {
+		if j != Joystick2 {
+			t.Errorf("joystick callback called for %v", j)
+		}
+		events = append(events, event)
+	})
+	Joystick2.InjectConnect("Test Pad", testGUID)
+	if !Joystick2.Present() || !Joystick2.IsGamepad() {
+		t.Error("injected gamepad not present or not a gamepad")
+	}
+	if name, guid := Joystick2.GetGamepadName(), Joystick2.GetGUID(); name != "Test Pad" || guid != testGUID {
+		t.Errorf("injected gamepad named %q with GUID %q", name, guid)
+	}
+	if state, ok := Joystick2.GetGamepadState(); !ok || *state != (GamepadState{}) {
+		t.Errorf("GetGamepadState() of a new gamepad = %v, %v, want a neutral state", state, ok)
+	}
+
+	var state GamepadState
+	state.Buttons[GamepadButtonStart] = Press
+	state.Axes[GamepadAxisRightY] = 0.5
+	Joystick2.InjectGamepadState(&state)
+	state.Buttons[GamepadButtonStart] = Release
+	if got, _ := Joystick2.GetGamepadState(); got.Buttons[GamepadButtonStart] != Press || got.Axes[GamepadAxisRightY] != 0.5 {
+		t.Errorf("GetGamepadState() = %v, want the injected state", got)
+	}
+	if buttons := Joystick2.GetButtons(); buttons[GamepadButtonStart] != Press {
+		t.Errorf("GetButtons() = %v, want the buttons of the injected state", buttons)
+	}
+	Joystick2.InjectGamepadState(nil)
+	if got, _ := Joystick2.GetGamepadState(); *got != (GamepadState{}) {
+		t.Errorf("GetGamepadState() after injecting nil = %v, want a neutral state", got)
+	}
+
+	Joystick2.InjectDisconnect()
+	Joystick2.InjectDisconnect()
+	Joystick3.InjectGamepadState(&state)
+	if Joystick2.Present() || Joystick2.GetGUID() != "" {
+		t.Error("disconnected gamepad still present")
+	}
+	if want := []ConnectionEvent{Connected, Disconnected}; !reflect.DeepEqual(events, want) {
+		t.Errorf("joystick callback called with %v, want %v", events, want)
+	}
+}
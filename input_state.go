@@ -0,0 +1,269 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package glfw
+
+// InputState tracks the keyboard, mouse and text input of a window frame by
+// frame.
+//
+// Besides the current state of keys and mouse buttons, it reports the presses
+// and releases that happened during the current frame, even if a key was
+// pressed and released again between two frames, as well as the cursor and
+// scroll movement and the text typed during the frame. A frame ends when
+// InputState.NextFrame() is called, usually once per iteration of the main
+// loop after the input has been handled:
+//
+//	input := glfw.NewInputState(win)
+//	for !win.ShouldClose() {
+//		ctx.PollEvents()
+//		if input.JustPressed(glfw.KeySpace) {
+//			// Jump.
+//		}
+//		input.NextFrame()
+//	}
+//
+// InputState receives the events of win alongside its callbacks, which remain
+// free to be set for other purposes. When win loses input focus, the keys and
+// mouse buttons held down are released, as the window does not receive their
+// releases. The state is closed when win is destroyed.
+type InputState struct {
+	win *Window
+
+	keys    [KeyLast + 1]buttonState
+	buttons [MouseButtonLast + 1]buttonState
+	mods    ModifierFlag
+
+	cursorX, cursorY         float64
+	lastCursorX, lastCursorY float64
+	scrollX, scrollY         float64
+	text                     []rune
+}
+
+// buttonState is the state of a key or mouse button.
+type buttonState struct {
+	// down : Whether the key or button is held down.
+	down bool
+	// pressed : Whether the key or button was pressed during the frame.
+	pressed bool
+	// released : Whether the key or button was released during the frame.
+	released bool
+	// repeats : The number of key repeats during the frame.
+	repeats int
+}
+
+// update updates s with action.
+func (s *buttonState) update(action Action) {
+	switch action {
+	case Press:
+		s.down = true
+		s.pressed = true
+	case Release:
+		s.down = false
+		s.released = true
+	case Repeat:
+		s.down = true
+		s.repeats++
+	}
+}
+
+// inputStates are the input states created with NewInputState() and not yet
+// closed.
+var inputStates []*InputState
+
+// NewInputState creates an InputState tracking the input of win. The state
+// starts with all keys and mouse buttons released and the current cursor
+// position.
+//
+// Call InputState.Close() when the state is no longer needed.
+//
+// This function must only be called from the main thread.
+func NewInputState(win *Window) *InputState {
+	s := &InputState{win: win}
+	s.cursorX, s.cursorY = win.GetCursorPos()
+	s.lastCursorX, s.lastCursorY = s.cursorX, s.cursorY
+
+	enableEvents()
+	inputStates = append(inputStates, s)
+	return s
+}
+
+// Close stops tracking the input. The state is no longer updated.
+//
+// This function must only be called from the main thread.
+func (s *InputState) Close() {
+	for i, state := range inputStates {
+		if state == s {
+			inputStates = append(inputStates[:i], inputStates[i+1:]...)
+			return
+		}
+	}
+}
+
+// closeInputStates closes the input states of win, before win is destroyed.
+func closeInputStates(win *Window) {
+	states := inputStates[:0]
+	for _, s := range inputStates {
+		if s.win != win {
+			states = append(states, s)
+		}
+	}
+	for i := len(states); i < len(inputStates); i++ {
+		inputStates[i] = nil
+	}
+	inputStates = states
+}
+
+// NextFrame ends the current frame. The presses, releases and repeats, the
+// cursor and scroll deltas and the text of the frame are reset, while keys and
+// mouse buttons held down remain down.
+//
+// This function must only be called from the main thread.
+func (s *InputState) NextFrame() {
+	for i := range s.keys {
+		s.keys[i].pressed, s.keys[i].released, s.keys[i].repeats = false, false, 0
+	}
+	for i := range s.buttons {
+		s.buttons[i].pressed, s.buttons[i].released, s.buttons[i].repeats = false, false, 0
+	}
+	s.lastCursorX, s.lastCursorY = s.cursorX, s.cursorY
+	s.scrollX, s.scrollY = 0, 0
+	s.text = s.text[:0]
+}
+
+// IsDown returns whether key is held down.
+func (s *InputState) IsDown(key Key) bool {
+	return s.key(key).down
+}
+
+// JustPressed returns whether key was pressed during the current frame.
+func (s *InputState) JustPressed(key Key) bool {
+	return s.key(key).pressed
+}
+
+// JustReleased returns whether key was released during the current frame.
+func (s *InputState) JustReleased(key Key) bool {
+	return s.key(key).released
+}
+
+// Repeats returns the number of times key was repeated while held down during
+// the current frame.
+func (s *InputState) Repeats(key Key) int {
+	return s.key(key).repeats
+}
+
+// IsButtonDown returns whether button is held down.
+func (s *InputState) IsButtonDown(button Button) bool {
+	return s.button(button).down
+}
+
+// ButtonJustPressed returns whether button was pressed during the current
+// frame.
+func (s *InputState) ButtonJustPressed(button Button) bool {
+	return s.button(button).pressed
+}
+
+// ButtonJustReleased returns whether button was released during the current
+// frame.
+func (s *InputState) ButtonJustReleased(button Button) bool {
+	return s.button(button).released
+}
+
+// Mods returns the modifier keys held down in the last key or mouse button
+// event.
+func (s *InputState) Mods() ModifierFlag {
+	return s.mods
+}
+
+// CursorPos returns the last cursor position, relative to the upper-left
+// corner of the content area of the window.
+func (s *InputState) CursorPos() (x, y float64) {
+	return s.cursorX, s.cursorY
+}
+
+// CursorDelta returns the cursor movement during the current frame.
+func (s *InputState) CursorDelta() (dx, dy float64) {
+	return s.cursorX - s.lastCursorX, s.cursorY - s.lastCursorY
+}
+
+// ScrollDelta returns the sum of the scroll offsets during the current frame.
+func (s *InputState) ScrollDelta() (dx, dy float64) {
+	return s.scrollX, s.scrollY
+}
+
+// Text returns the text typed during the current frame.
+func (s *InputState) Text() string {
+	return string(s.text)
+}
+
+// key returns the state of key, or an empty state if key is out of range.
+func (s *InputState) key(key Key) buttonState {
+	if key < 0 || key > KeyLast {
+		return buttonState{}
+	}
+	return s.keys[key]
+}
+
+// button returns the state of button, or an empty state if button is out of
+// range.
+func (s *InputState) button(button Button) buttonState {
+	if button < 0 || button > MouseButtonLast {
+		return buttonState{}
+	}
+	return s.buttons[button]
+}
+
+// handle updates s with event, if it is an input event of the window of s.
+func (s *InputState) handle(event Event) {
+	switch e := event.(type) {
+	case KeyEvent:
+		if e.Window != s.win {
+			return
+		}
+		if e.Key >= 0 && e.Key <= KeyLast {
+			s.keys[e.Key].update(e.Action)
+		}
+		s.mods = e.Mods
+	case MouseButtonEvent:
+		if e.Window != s.win {
+			return
+		}
+		if e.Button >= 0 && e.Button <= MouseButtonLast {
+			s.buttons[e.Button].update(e.Action)
+		}
+		s.mods = e.Mods
+	case CursorPosEvent:
+		if e.Window == s.win {
+			s.cursorX, s.cursorY = e.X, e.Y
+		}
+	case ScrollEvent:
+		if e.Window == s.win {
+			s.scrollX += e.XOffset
+			s.scrollY += e.YOffset
+		}
+	case CharEvent:
+		if e.Window == s.win {
+			s.text = append(s.text, e.Codepoint)
+		}
+	case FocusEvent:
+		if e.Window == s.win && !e.Focused {
+			s.releaseAll()
+		}
+	}
+}
+
+// releaseAll releases the keys and mouse buttons held down.
+func (s *InputState) releaseAll() {
+	for i := range s.keys {
+		if s.keys[i].down {
+			s.keys[i].update(Release)
+		}
+	}
+	for i := range s.buttons {
+		if s.buttons[i].down {
+			s.buttons[i].update(Release)
+		}
+	}
+	s.mods = 0
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package glfw
+
+import "testing"
+
+func TestInputStateEdges(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	defer win.Destroy()
+	input := NewInputState(win)
+	defer input.Close()
+
+	// A key pressed and released again within a frame.
+	win.InjectKey(KeyA, 0, Press, 0)
+	win.InjectKey(KeyA, 0, Release, 0)
+	// A key held down and repeated.
+	win.InjectKey(KeyB, 0, Press, ModShift)
+	win.InjectKey(KeyB, 0, Repeat, ModShift)
+	win.InjectKey(KeyB, 0, Repeat, ModShift)
+	win.InjectMouseButton(MouseButtonRight, Press, ModControl)
+
+	if !input.JustPressed(KeyA) || !input.JustReleased(KeyA) || input.IsDown(KeyA) {
+		t.Error("key pressed and released within a frame not reported as both")
+	}
+	if !input.JustPressed(KeyB) || !input.IsDown(KeyB) || input.Repeats(KeyB) != 2 {
+		t.Errorf("held key: pressed %v, down %v, repeats %d", input.JustPressed(KeyB), input.IsDown(KeyB), input.Repeats(KeyB))
+	}
+	if !input.ButtonJustPressed(MouseButtonRight) || !input.IsButtonDown(MouseButtonRight) {
+		t.Error("mouse button press not reported")
+	}
+	if input.Mods() != ModControl {
+		t.Errorf("Mods() = %v, want the mods of the last event", input.Mods())
+	}
+	if input.IsDown(Key(-1)) || input.IsDown(KeyLast+1) || input.IsButtonDown(MouseButtonLast+1) {
+		t.Error("out of range key or button reported down")
+	}
+
+	input.NextFrame()
+	if input.JustPressed(KeyA) || input.JustReleased(KeyA) || input.JustPressed(KeyB) || input.Repeats(KeyB) != 0 {
+		t.Error("edges or repeats of the previous frame still reported")
+	}
+	if !input.IsDown(KeyB) || !input.IsButtonDown(MouseButtonRight) {
+		t.Error("keys or buttons held down released by NextFrame()")
+	}
+
+	win.InjectMouseButton(MouseButtonRight, Release, 0)
+	if !input.ButtonJustReleased(MouseButtonRight) || input.IsButtonDown(MouseButtonRight) {
+		t.Error("mouse button release not reported")
+	}
+}
+
+func TestInputStateDeltas(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	defer win.Destroy()
+	win.InjectCursorPos(10, 10)
+	input := NewInputState(win)
+	defer input.Close()
+
+	if x, y := input.CursorPos(); x != 10 || y != 10 {
+		t.Errorf("initial CursorPos() = %v, %v, want 10, 10", x, y)
+	}
+	win.InjectCursorPos(15, 8)
+	win.InjectCursorPos(20, 4)
+	win.InjectScroll(1, -1)
+	win.InjectScroll(0.5, -2)
+	win.InjectChar('h', 0)
+	win.InjectChar('é', 0)
+	if dx, dy := input.CursorDelta(); dx != 10 || dy != -6 {
+		t.Errorf("CursorDelta() = %v, %v, want 10, -6", dx, dy)
+	}
+	if dx, dy := input.ScrollDelta(); dx != 1.5 || dy != -3 {
+		t.Errorf("ScrollDelta() = %v, %v, want 1.5, -3", dx, dy)
+	}
+	if text := input.Text(); text != "hé" {
+		t.Errorf("Text() = %q, want %q", text, "hé")
+	}
+
+	input.NextFrame()
+	if dx, dy := input.CursorDelta(); dx != 0 || dy != 0 {
+		t.Errorf("CursorDelta() after NextFrame() = %v, %v", dx, dy)
+	}
+	if dx, dy := input.ScrollDelta(); dx != 0 || dy != 0 {
+		t.Errorf("ScrollDelta() after NextFrame() = %v, %v", dx, dy)
+	}
+	if text := input.Text(); text != "" {
+		t.Errorf("Text() after NextFrame() = %q", text)
+	}
+	if x, y := input.CursorPos(); x != 20 || y != 4 {
+		t.Errorf("CursorPos() = %v, %v, want 20, 4", x, y)
+	}
+}
+
+func TestInputStateFocusLoss(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	defer win.Destroy()
+	other := createTestWindow(t, ctx)
+	defer other.Destroy()
+	input := NewInputState(win)
+	defer input.Close()
+
+	win.InjectKey(KeyW, 0, Press, ModShift)
+	win.InjectMouseButton(MouseButtonLeft, Press, ModShift)
+	input.NextFrame()
+	dispatchEvent(FocusEvent{Window: other, Focused: false})
+	if !input.IsDown(KeyW) {
+		t.Fatal("focus loss of another window released the keys")
+	}
+	dispatchEvent(FocusEvent{Window: win, Focused: false})
+	if input.IsDown(KeyW) || !input.JustReleased(KeyW) {
+		t.Error("key held down not released on focus loss")
+	}
+	if input.IsButtonDown(MouseButtonLeft) || !input.ButtonJustReleased(MouseButtonLeft) {
+		t.Error("mouse button held down not released on focus loss")
+	}
+	if input.Mods() != 0 {
+		t.Errorf("Mods() after focus loss = %v, want none", input.Mods())
+	}
+}
+
+func TestInputStateWindowDestroyed(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	other := createTestWindow(t, ctx)
+	defer other.Destroy()
+	NewInputState(win)
+	NewInputState(win)
+	kept := NewInputState(other)
+	defer kept.Close()
+
+	win.Destroy()
+	if len(inputStates) != 1 || inputStates[0] != kept {
+		t.Errorf("input states after the window was destroyed = %v, want only that of the other window", inputStates)
+	}
+}
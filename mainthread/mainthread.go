@@ -0,0 +1,164 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// Package mainthread runs functions on the main OS thread, which most GLFW
+// functions must be called from.
+//
+// The main goroutine is locked to the main OS thread when this package is
+// initialized. Run takes over the main goroutine and runs the application in
+// another goroutine, while Call and CallErr execute functions on the main
+// thread from any goroutine:
+//
+//	func main() {
+//		mainthread.Run(run)
+//	}
+//
+//	func run() {
+//		var ctx *glfw.Context
+//		mainthread.Call(func() {
+//			ctx = glfw.Init()
+//		})
+//		defer mainthread.Call(ctx.Terminate)
+//		...
+//		for {
+//			mainthread.WaitEvents(ctx)
+//			...
+//		}
+//	}
+package mainthread
+
+import (
+	"runtime"
+	"sync/atomic"
+
+	"github.com/beta/glfw"
+)
+
+func init() {
+	runtime.LockOSThread()
+}
+
+var (
+	// calls receives the functions to run on the main thread.
+	calls = make(chan func())
+
+	// pending is the number of calls waiting to be received by the main thread.
+	pending int32
+
+	// wake wakes up the main thread while it waits for events, and is nil
+	// otherwise. It holds a func(), such as Context.PostEmptyEvent of the
+	// context the main thread waits with.
+	wake atomic.Value
+)
+
+// Run runs run in a new goroutine and executes the functions passed to Call and
+// CallErr on the main thread until run returns.
+//
+// Run must be called from the main goroutine, usually from main. Functions
+// passed to Call before Run are executed once Run is called.
+func Run(run func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run()
+	}()
+
+	for {
+		select {
+		case fn := <-calls:
+			atomic.AddInt32(&pending, -1)
+			fn()
+		case <-done:
+			return
+		}
+	}
+}
+
+// Call executes fn on the main thread and returns once fn has returned.
+//
+// If the main thread is waiting for events in WaitEvents or WaitEventsTimeout,
+// it is woken up with Context.PostEmptyEvent() to execute fn.
+//
+// If fn panics, the panic is recovered on the main thread, so that Run keeps
+// executing the other calls, and Call panics again with the same value. The
+// stack trace of the panic is that of Call, not of fn.
+//
+// Call must not be called from the main thread, i.e. from a function passed to
+// Call or CallErr, as that would deadlock.
+func Call(fn func()) {
+	done := make(chan struct{})
+	var (
+		panicked bool
+		value    interface{}
+	)
+	atomic.AddInt32(&pending, 1)
+	if wake, _ := wake.Load().(func()); wake != nil {
+		wake()
+	}
+	calls <- func() {
+		defer close(done)
+		defer func() {
+			if panicked {
+				value = recover()
+			}
+		}()
+		panicked = true
+		fn()
+		panicked = false
+	}
+	<-done
+	if panicked {
+		panic(value)
+	}
+}
+
+// CallErr is like Call, but returns the error returned by fn.
+func CallErr(fn func() error) error {
+	var err error
+	Call(func() {
+		err = fn()
+	})
+	return err
+}
+
+// WaitEvents calls Context.WaitEvents() on the main thread. Unlike calling it
+// with Call, the wait is cut short when another goroutine calls Call or
+// CallErr, so that their functions do not wait for the next event: WaitEvents
+// then returns without waiting for an event, as after Context.PostEmptyEvent(),
+// and the pending functions are executed afterwards, possibly after
+// WaitEvents has returned.
+//
+// This function may be called from any goroutine except the main thread.
+func WaitEvents(ctx *glfw.Context) {
+	Call(func() {
+		wait(ctx.PostEmptyEvent, ctx.WaitEvents)
+	})
+}
+
+// WaitEventsTimeout is like WaitEvents, but calls
+// Context.WaitEventsTimeout() with timeout, in seconds.
+//
+// This function may be called from any goroutine except the main thread.
+func WaitEventsTimeout(ctx *glfw.Context, timeout float64) {
+	Call(func() {
+		wait(ctx.PostEmptyEvent, func() {
+			ctx.WaitEventsTimeout(timeout)
+		})
+	})
+}
+
+// wait calls waitFn on the main thread, unless calls are already pending. The
+// calls made while waitFn waits call post to wake it up.
+//
+// wake is set before pending is checked, while Call increments pending before
+// loading wake, so a call either is seen here or wakes up waitFn.
+func wait(post func(), waitFn func()) {
+	wake.Store(post)
+	defer wake.Store((func())(nil))
+	if atomic.LoadInt32(&pending) > 0 {
+		return
+	}
+	waitFn()
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package mainthread
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/beta/glfw"
+)
+
+func TestMain(m *testing.M) {
+	var code int
+	Run(func() {
+		code = m.Run()
+	})
+	os.Exit(code)
+}
+
+func TestCallOrder(t *testing.T) {
+	var calls []int
+	for i := 0; i < 10; i++ {
+		i := i
+		Call(func() {
+			calls = append(calls, i)
+		})
+	}
+	errCall := errors.New("call")
+	var errCalls []int
+	for i := 10; i < 20; i++ {
+		i := i
+		err := CallErr(func() error {
+			errCalls = append(errCalls, i)
+			return errCall
+		})
+		if err != errCall {
+			t.Errorf("CallErr() = %v, want the error of the function", err)
+		}
+	}
+	calls = append(calls, errCalls...)
+	for i, call := range calls {
+		if call != i {
+			t.Fatalf("functions executed in the order %v", calls)
+		}
+	}
+
+	// Concurrent calls are executed one at a time.
+	var wg sync.WaitGroup
+	count := 0
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Call(func() {
+				count++
+			})
+		}()
+	}
+	wg.Wait()
+	if count != 100 {
+		t.Errorf("100 concurrent calls counted %d", count)
+	}
+}
+
+func TestCallPanic(t *testing.T) {
+	func() {
+		defer func() {
+			if r := recover(); r != "call" {
+				t.Errorf("Call() panicked with %v, want the panic of the function", r)
+			}
+		}()
+		Call(func() {
+			panic("call")
+		})
+	}()
+
+	// The main thread keeps executing calls after the panic.
+	called := false
+	Call(func() {
+		called = true
+	})
+	if !called {
+		t.Error("function not executed after a panic")
+	}
+}
+
+func TestCallWakesWait(t *testing.T) {
+	// The null platform does not block in Context.WaitEvents(), so the wait is
+	// simulated by a function that blocks until it is woken up.
+	woken := make(chan struct{}, 1)
+	post := func() {
+		woken <- struct{}{}
+	}
+	waiting := make(chan struct{})
+	waited := make(chan struct{})
+	go func() {
+		defer close(waited)
+		Call(func() {
+			wait(post, func() {
+				close(waiting)
+				<-woken
+			})
+		})
+	}()
+
+	<-waiting
+	called := make(chan struct{})
+	go func() {
+		Call(func() {})
+		close(called)
+	}()
+	select {
+	case <-called:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Call() did not wake up the main thread")
+	}
+	<-waited
+}
+
+func TestWaitEvents(t *testing.T) {
+	var ctx *glfw.Context
+	err := CallErr(func() (err error) {
+		ctx, err = glfw.InitErr()
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer Call(ctx.Terminate)
+
+	WaitEvents(ctx)
+	WaitEventsTimeout(ctx, 0.01)
+	if post, _ := wake.Load().(func()); post != nil {
+		t.Error("main thread still woken up by calls after WaitEvents returned")
+	}
+}
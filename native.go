@@ -0,0 +1,34 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package glfw
+
+/*
+#include "glfw/include/GLFW/glfw3.h"
+*/
+import "C"
+import "fmt"
+
+// NativeAPIError is returned by the native handle accessors when the requested
+// native API is not the one GLFW was built with, e.g. when calling
+// Window.GetX11Window() on Windows or in a build with the wayland tag.
+type NativeAPIError struct {
+	// API : The name of the requested native API, e.g. "X11" or "EGL".
+	API string
+}
+
+func (err *NativeAPIError) Error() string {
+	return fmt.Sprintf("glfw: %s native access is not available in this build", err.API)
+}
+
+// nativeError returns an error describing why a native accessor of api
+// returned no handle, using err, the error reported by the accessor, if there
+// is one.
+func nativeError(err error, api string) error {
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("glfw: %s: no native handle available", api)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build darwin
+
+package glfw
+
+/*
+#define GLFW_EXPOSE_NATIVE_COCOA
+#define GLFW_EXPOSE_NATIVE_NSGL
+#include "glfw/include/GLFW/glfw3.h"
+#include "glfw/include/GLFW/glfw3native.h"
+
+// Cgo cannot translate the Objective-C id type, so the handles are passed back
+// as void* instead.
+static void* goGetCocoaWindow(GLFWwindow* window) {
+	return (void*) glfwGetCocoaWindow(window);
+}
+
+static void* goGetNSGLContext(GLFWwindow* window) {
+	return (void*) glfwGetNSGLContext(window);
+}
+*/
+import "C"
+import "unsafe"
+
+// GetCocoaMonitor returns the CGDirectDisplayID of monitor.
+//
+// Possible errors include NotInitialized.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (monitor *Monitor) GetCocoaMonitor() (uint32, error) {
+	var display uint32
+	err := catchError(func() { display = uint32(C.glfwGetCocoaMonitor(monitor.c())) })
+	if display == 0 {
+		return 0, nativeError(err, "Cocoa")
+	}
+	return display, nil
+}
+
+// GetCocoaWindow returns the NSWindow of win.
+//
+// Possible errors include NotInitialized.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (win *Window) GetCocoaWindow() (unsafe.Pointer, error) {
+	var window unsafe.Pointer
+	err := catchError(func() { window = C.goGetCocoaWindow(win.c()) })
+	if window == nil {
+		return nil, nativeError(err, "Cocoa")
+	}
+	return window, nil
+}
+
+// GetNSGLContext returns the NSOpenGLContext of win.
+//
+// Possible errors include NotInitialized and NoWindowContext.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (win *Window) GetNSGLContext() (unsafe.Pointer, error) {
+	var context unsafe.Pointer
+	err := catchError(func() { context = C.goGetNSGLContext(win.c()) })
+	if context == nil {
+		return nil, nativeError(err, "NSGL")
+	}
+	return context, nil
+}
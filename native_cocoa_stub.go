@@ -0,0 +1,34 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build !darwin
+
+package glfw
+
+import "unsafe"
+
+// GetCocoaMonitor returns the CGDirectDisplayID of monitor.
+//
+// GLFW is not built with the Cocoa backend, so this function always returns a
+// NativeAPIError.
+func (monitor *Monitor) GetCocoaMonitor() (uint32, error) {
+	return 0, &NativeAPIError{API: "Cocoa"}
+}
+
+// GetCocoaWindow returns the NSWindow of win.
+//
+// GLFW is not built with the Cocoa backend, so this function always returns a
+// NativeAPIError.
+func (win *Window) GetCocoaWindow() (unsafe.Pointer, error) {
+	return nil, &NativeAPIError{API: "Cocoa"}
+}
+
+// GetNSGLContext returns the NSOpenGLContext of win.
+//
+// GLFW is not built with the Cocoa backend, so this function always returns a
+// NativeAPIError.
+func (win *Window) GetNSGLContext() (unsafe.Pointer, error) {
+	return nil, &NativeAPIError{API: "NSGL"}
+}
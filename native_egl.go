@@ -0,0 +1,63 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build windows linux,!glfw_null
+
+package glfw
+
+/*
+#include "glfw/include/GLFW/glfw3.h"
+
+// These are declared in glfw3native.h, which requires EGL/egl.h. Like GLFW
+// itself, the EGL handle types are declared as opaque pointers here so that no
+// EGL headers are needed.
+void* glfwGetEGLDisplay(void);
+void* glfwGetEGLContext(GLFWwindow* window);
+void* glfwGetEGLSurface(GLFWwindow* window);
+*/
+import "C"
+import "unsafe"
+
+// GetEGLDisplay returns the EGLDisplay used by GLFW.
+//
+// Possible errors include NotInitialized.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (c *Context) GetEGLDisplay() (unsafe.Pointer, error) {
+	var display unsafe.Pointer
+	err := catchError(func() { display = unsafe.Pointer(C.glfwGetEGLDisplay()) })
+	if display == nil {
+		return nil, nativeError(err, "EGL")
+	}
+	return display, nil
+}
+
+// GetEGLContext returns the EGLContext of win.
+//
+// Possible errors include NotInitialized and NoWindowContext.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (win *Window) GetEGLContext() (unsafe.Pointer, error) {
+	var context unsafe.Pointer
+	err := catchError(func() { context = unsafe.Pointer(C.glfwGetEGLContext(win.c())) })
+	if context == nil {
+		return nil, nativeError(err, "EGL")
+	}
+	return context, nil
+}
+
+// GetEGLSurface returns the EGLSurface of win.
+//
+// Possible errors include NotInitialized and NoWindowContext.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (win *Window) GetEGLSurface() (unsafe.Pointer, error) {
+	var surface unsafe.Pointer
+	err := catchError(func() { surface = unsafe.Pointer(C.glfwGetEGLSurface(win.c())) })
+	if surface == nil {
+		return nil, nativeError(err, "EGL")
+	}
+	return surface, nil
+}
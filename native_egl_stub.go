@@ -0,0 +1,34 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build !windows,!linux !windows,glfw_null
+
+package glfw
+
+import "unsafe"
+
+// GetEGLDisplay returns the EGLDisplay used by GLFW.
+//
+// GLFW is not built with EGL support, so this function always returns a
+// NativeAPIError.
+func (c *Context) GetEGLDisplay() (unsafe.Pointer, error) {
+	return nil, &NativeAPIError{API: "EGL"}
+}
+
+// GetEGLContext returns the EGLContext of win.
+//
+// GLFW is not built with EGL support, so this function always returns a
+// NativeAPIError.
+func (win *Window) GetEGLContext() (unsafe.Pointer, error) {
+	return nil, &NativeAPIError{API: "EGL"}
+}
+
+// GetEGLSurface returns the EGLSurface of win.
+//
+// GLFW is not built with EGL support, so this function always returns a
+// NativeAPIError.
+func (win *Window) GetEGLSurface() (unsafe.Pointer, error) {
+	return nil, &NativeAPIError{API: "EGL"}
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package glfw
+
+/*
+#include "glfw/include/GLFW/glfw3.h"
+
+// These are declared in glfw3native.h, which requires GL/osmesa.h for the
+// OSMesaContext type. OSMesaContext is an opaque pointer, so it is declared as
+// void* here instead.
+int glfwGetOSMesaColorBuffer(GLFWwindow* window, int* width, int* height, int* format, void** buffer);
+int glfwGetOSMesaDepthBuffer(GLFWwindow* window, int* width, int* height, int* bytesPerValue, void** buffer);
+void* glfwGetOSMesaContext(GLFWwindow* window);
+*/
+import "C"
+import (
+	"image"
+	"unsafe"
+)
+
+// OSMesa pixel formats of color buffers.
+const (
+	osmesaBGRA = 0x1
+	osmesaARGB = 0x2
+	osmesaRGBA = 0x1908
+)
+
+// GetOSMesaContext returns the OSMesaContext of win, or nil if an error
+// occurred.
+//
+// Possible errors include NotInitialized and NoWindowContext.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (win *Window) GetOSMesaContext() unsafe.Pointer {
+	return unsafe.Pointer(C.glfwGetOSMesaContext(win.c()))
+}
+
+// GetOSMesaColorBuffer returns a copy of the color buffer of the OSMesa
+// context of win, or nil if an error occurred.
+//
+// The rows of the returned image are ordered top-to-bottom, i.e. the image
+// looks the same as the window would on screen. The pixel values are copied as
+// they were rendered, without any alpha premultiplication.
+//
+// win must have been created with the ContextCreationAPI hint set to
+// OSMesaContextAPI, or on the null platform. The context does not need to be
+// current, but rendering must have finished, e.g. by calling glFinish, for the
+// returned image to contain the latest frame.
+//
+// Possible errors include NotInitialized, NoWindowContext and PlatformError.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (win *Window) GetOSMesaColorBuffer() *image.RGBA {
+	if win.GetOSMesaContext() == nil {
+		return nil
+	}
+
+	var cWidth, cHeight, cFormat C.int
+	var cBuffer unsafe.Pointer
+	if int(C.glfwGetOSMesaColorBuffer(win.c(), &cWidth, &cHeight, &cFormat, &cBuffer)) != True {
+		return nil
+	}
+	width, height, format := int(cWidth), int(cHeight), int(cFormat)
+	if cBuffer == nil || width <= 0 || height <= 0 {
+		return nil
+	}
+
+	buffer := C.GoBytes(cBuffer, C.int(width*4*height))
+	return osmesaColorImage(buffer, width, height, format)
+}
+
+// osmesaColorImage returns the image of an OSMesa color buffer of width *
+// height pixels in format, whose bottom row is stored first, or nil if format
+// is not supported.
+func osmesaColorImage(buffer []byte, width, height, format int) *image.RGBA {
+	var order [4]int // Offsets of the red, green, blue and alpha channels.
+	switch format {
+	case osmesaRGBA:
+		order = [4]int{0, 1, 2, 3}
+	case osmesaBGRA:
+		order = [4]int{2, 1, 0, 3}
+	case osmesaARGB:
+		order = [4]int{1, 2, 3, 0}
+	default:
+		return nil
+	}
+
+	stride := width * 4
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		// OSMesa stores the bottom row first.
+		src := buffer[(height-1-y)*stride : (height-y)*stride]
+		dst := img.Pix[y*img.Stride : y*img.Stride+stride]
+		if format == osmesaRGBA {
+			copy(dst, src)
+			continue
+		}
+		for x := 0; x < stride; x += 4 {
+			dst[x+0] = src[x+order[0]]
+			dst[x+1] = src[x+order[1]]
+			dst[x+2] = src[x+order[2]]
+			dst[x+3] = src[x+order[3]]
+		}
+	}
+	return img
+}
+
+// GetOSMesaDepthBuffer returns a copy of the depth buffer of the OSMesa
+// context of win. depth contains width * height values, with rows ordered
+// top-to-bottom like the image returned by Window.GetOSMesaColorBuffer().
+// Returns a nil depth if an error occurred.
+//
+// The depth values are returned as stored by OSMesa. 16-bit depth buffers are
+// widened without scaling and range from 0 to 0xFFFF, while 32-bit depth
+// buffers range from 0 to 0xFFFFFFFF.
+//
+// Possible errors include NotInitialized, NoWindowContext and PlatformError.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (win *Window) GetOSMesaDepthBuffer() (width, height int, depth []uint32) {
+	if win.GetOSMesaContext() == nil {
+		return
+	}
+
+	var cWidth, cHeight, cBytesPerValue C.int
+	var cBuffer unsafe.Pointer
+	if int(C.glfwGetOSMesaDepthBuffer(win.c(), &cWidth, &cHeight, &cBytesPerValue, &cBuffer)) != True {
+		return
+	}
+	w, h, bytesPerValue := int(cWidth), int(cHeight), int(cBytesPerValue)
+	if cBuffer == nil || w <= 0 || h <= 0 || (bytesPerValue != 2 && bytesPerValue != 4) {
+		return
+	}
+
+	buffer := C.GoBytes(cBuffer, C.int(w*bytesPerValue*h))
+	return w, h, osmesaDepthValues(buffer, w, h, bytesPerValue)
+}
+
+// osmesaDepthValues returns the values of an OSMesa depth buffer of width *
+// height values of bytesPerValue bytes, whose bottom row is stored first.
+func osmesaDepthValues(buffer []byte, width, height, bytesPerValue int) []uint32 {
+	stride := width * bytesPerValue
+	depth := make([]uint32, width*height)
+	for y := 0; y < height; y++ {
+		// OSMesa stores the bottom row first.
+		row := buffer[(height-1-y)*stride : (height-y)*stride]
+		for x := 0; x < width; x++ {
+			if bytesPerValue == 2 {
+				depth[y*width+x] = uint32(*(*uint16)(unsafe.Pointer(&row[x*2])))
+			} else {
+				depth[y*width+x] = *(*uint32)(unsafe.Pointer(&row[x*4]))
+			}
+		}
+	}
+	return depth
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package glfw
+
+import (
+	"image/color"
+	"testing"
+	"unsafe"
+)
+
+func TestOSMesaColorImage(t *testing.T) {
+	// A 2x2 buffer, bottom row first, with a distinct pixel per position.
+	pixels := [4][4]byte{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10, 11, 12}, {13, 14, 15, 16}}
+	var buffer []byte
+	for _, pixel := range pixels {
+		buffer = append(buffer, pixel[:]...)
+	}
+
+	tests := []struct {
+		format int
+		order  [4]int
+	}{
+		{osmesaRGBA, [4]int{0, 1, 2, 3}},
+		{osmesaBGRA, [4]int{2, 1, 0, 3}},
+		{osmesaARGB, [4]int{1, 2, 3, 0}},
+	}
+	for _, test := range tests {
+		img := osmesaColorImage(buffer, 2, 2, test.format)
+		if img == nil || img.Rect.Dx() != 2 || img.Rect.Dy() != 2 {
+			t.Fatalf("format %#x: osmesaColorImage() = %v", test.format, img)
+		}
+		for i, pixel := range pixels {
+			// The top row of the image is the last row of the buffer.
+			x, y := i%2, 1-i/2
+			o := test.order
+			want := color.RGBA{pixel[o[0]], pixel[o[1]], pixel[o[2]], pixel[o[3]]}
+			if got := img.RGBAAt(x, y); got != want {
+				t.Errorf("format %#x: pixel (%d, %d) = %v, want %v", test.format, x, y, got, want)
+			}
+		}
+	}
+
+	if img := osmesaColorImage(buffer, 2, 2, 0x1234); img != nil {
+		t.Errorf("osmesaColorImage() of an unknown format = %v, want nil", img)
+	}
+}
+
+func TestOSMesaDepthValues(t *testing.T) {
+	values16 := []uint16{1, 2, 3, 0xFFFF}
+	buffer16 := (*[8]byte)(unsafe.Pointer(&values16[0]))[:]
+	if depth := osmesaDepthValues(buffer16, 2, 2, 2); !equalUint32s(depth, []uint32{3, 0xFFFF, 1, 2}) {
+		t.Errorf("osmesaDepthValues() of 16-bit values = %v", depth)
+	}
+
+	values32 := []uint32{1, 2, 3, 0xFFFFFFFF}
+	buffer32 := (*[16]byte)(unsafe.Pointer(&values32[0]))[:]
+	if depth := osmesaDepthValues(buffer32, 2, 2, 4); !equalUint32s(depth, []uint32{3, 0xFFFFFFFF, 1, 2}) {
+		t.Errorf("osmesaDepthValues() of 32-bit values = %v", depth)
+	}
+}
+
+func equalUint32s(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestOSMesaReadback(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	ctx.WindowHint(ContextCreationAPI, OSMesaContextAPI)
+	win, err := ctx.CreateWindowErr(64, 32, "osmesa", nil, nil)
+	if err != nil {
+		t.Skipf("no OSMesa context: %v", err)
+	}
+	defer win.Destroy()
+
+	img := win.GetOSMesaColorBuffer()
+	if img == nil || img.Rect.Dx() != 64 || img.Rect.Dy() != 32 {
+		t.Fatalf("GetOSMesaColorBuffer() = %v, want a 64x32 image", img)
+	}
+	width, height, depth := win.GetOSMesaDepthBuffer()
+	if width != 64 || height != 32 || len(depth) != 64*32 {
+		t.Errorf("GetOSMesaDepthBuffer() = %d, %d, %d values, want 64x32", width, height, len(depth))
+	}
+}
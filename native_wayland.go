@@ -0,0 +1,58 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build linux,wayland,!glfw_null
+
+package glfw
+
+/*
+#define GLFW_EXPOSE_NATIVE_WAYLAND
+#include "glfw/include/GLFW/glfw3.h"
+#include "glfw/include/GLFW/glfw3native.h"
+*/
+import "C"
+import "unsafe"
+
+// GetWaylandDisplay returns the struct wl_display* used by GLFW.
+//
+// Possible errors include NotInitialized.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (c *Context) GetWaylandDisplay() (unsafe.Pointer, error) {
+	var display unsafe.Pointer
+	err := catchError(func() { display = unsafe.Pointer(C.glfwGetWaylandDisplay()) })
+	if display == nil {
+		return nil, nativeError(err, "Wayland")
+	}
+	return display, nil
+}
+
+// GetWaylandMonitor returns the struct wl_output* of monitor.
+//
+// Possible errors include NotInitialized.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (monitor *Monitor) GetWaylandMonitor() (unsafe.Pointer, error) {
+	var output unsafe.Pointer
+	err := catchError(func() { output = unsafe.Pointer(C.glfwGetWaylandMonitor(monitor.c())) })
+	if output == nil {
+		return nil, nativeError(err, "Wayland")
+	}
+	return output, nil
+}
+
+// GetWaylandSurface returns the main struct wl_surface* of win.
+//
+// Possible errors include NotInitialized.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (win *Window) GetWaylandSurface() (unsafe.Pointer, error) {
+	var surface unsafe.Pointer
+	err := catchError(func() { surface = unsafe.Pointer(C.glfwGetWaylandWindow(win.c())) })
+	if surface == nil {
+		return nil, nativeError(err, "Wayland")
+	}
+	return surface, nil
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build !linux !wayland glfw_null
+
+package glfw
+
+import "unsafe"
+
+// GetWaylandDisplay returns the struct wl_display* used by GLFW.
+//
+// GLFW is not built with the Wayland backend, so this function always returns
+// a NativeAPIError.
+func (c *Context) GetWaylandDisplay() (unsafe.Pointer, error) {
+	return nil, &NativeAPIError{API: "Wayland"}
+}
+
+// GetWaylandMonitor returns the struct wl_output* of monitor.
+//
+// GLFW is not built with the Wayland backend, so this function always returns
+// a NativeAPIError.
+func (monitor *Monitor) GetWaylandMonitor() (unsafe.Pointer, error) {
+	return nil, &NativeAPIError{API: "Wayland"}
+}
+
+// GetWaylandSurface returns the main struct wl_surface* of win.
+//
+// GLFW is not built with the Wayland backend, so this function always returns
+// a NativeAPIError.
+func (win *Window) GetWaylandSurface() (unsafe.Pointer, error) {
+	return nil, &NativeAPIError{API: "Wayland"}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build windows
+
+package glfw
+
+/*
+#define GLFW_EXPOSE_NATIVE_WIN32
+#define GLFW_EXPOSE_NATIVE_WGL
+#include "glfw/include/GLFW/glfw3.h"
+#include "glfw/include/GLFW/glfw3native.h"
+*/
+import "C"
+import "unsafe"
+
+// GetWin32Adapter returns the adapter device name of monitor, encoded as
+// UTF-8, e.g. \\.\DISPLAY1.
+//
+// Possible errors include NotInitialized.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (monitor *Monitor) GetWin32Adapter() (string, error) {
+	var cName *C.char
+	err := catchError(func() { cName = C.glfwGetWin32Adapter(monitor.c()) })
+	if unsafe.Pointer(cName) == C.NULL {
+		return "", nativeError(err, "Win32")
+	}
+	return C.GoString(cName), nil
+}
+
+// GetWin32Monitor returns the display device name of monitor, encoded as UTF-8,
+// e.g. \\.\DISPLAY1\Monitor0.
+//
+// Possible errors include NotInitialized.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (monitor *Monitor) GetWin32Monitor() (string, error) {
+	var cName *C.char
+	err := catchError(func() { cName = C.glfwGetWin32Monitor(monitor.c()) })
+	if unsafe.Pointer(cName) == C.NULL {
+		return "", nativeError(err, "Win32")
+	}
+	return C.GoString(cName), nil
+}
+
+// GetWin32Window returns the HWND of win.
+//
+// Possible errors include NotInitialized.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (win *Window) GetWin32Window() (uintptr, error) {
+	var hwnd uintptr
+	err := catchError(func() { hwnd = uintptr(unsafe.Pointer(C.glfwGetWin32Window(win.c()))) })
+	if hwnd == 0 {
+		return 0, nativeError(err, "Win32")
+	}
+	return hwnd, nil
+}
+
+// GetWGLContext returns the HGLRC of win.
+//
+// Possible errors include NotInitialized and NoWindowContext.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (win *Window) GetWGLContext() (uintptr, error) {
+	var hglrc uintptr
+	err := catchError(func() { hglrc = uintptr(unsafe.Pointer(C.glfwGetWGLContext(win.c()))) })
+	if hglrc == 0 {
+		return 0, nativeError(err, "WGL")
+	}
+	return hglrc, nil
+}
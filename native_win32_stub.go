@@ -0,0 +1,42 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build !windows
+
+package glfw
+
+// GetWin32Adapter returns the adapter device name of monitor, encoded as
+// UTF-8, e.g. \\.\DISPLAY1.
+//
+// GLFW is not built with the Win32 backend, so this function always returns a
+// NativeAPIError.
+func (monitor *Monitor) GetWin32Adapter() (string, error) {
+	return "", &NativeAPIError{API: "Win32"}
+}
+
+// GetWin32Monitor returns the display device name of monitor, encoded as UTF-8,
+// e.g. \\.\DISPLAY1\Monitor0.
+//
+// GLFW is not built with the Win32 backend, so this function always returns a
+// NativeAPIError.
+func (monitor *Monitor) GetWin32Monitor() (string, error) {
+	return "", &NativeAPIError{API: "Win32"}
+}
+
+// GetWin32Window returns the HWND of win.
+//
+// GLFW is not built with the Win32 backend, so this function always returns a
+// NativeAPIError.
+func (win *Window) GetWin32Window() (uintptr, error) {
+	return 0, &NativeAPIError{API: "Win32"}
+}
+
+// GetWGLContext returns the HGLRC of win.
+//
+// GLFW is not built with the Win32 backend, so this function always returns a
+// NativeAPIError.
+func (win *Window) GetWGLContext() (uintptr, error) {
+	return 0, &NativeAPIError{API: "WGL"}
+}
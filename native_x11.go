@@ -0,0 +1,101 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build linux,!wayland,!glfw_null
+
+package glfw
+
+/*
+#define GLFW_EXPOSE_NATIVE_X11
+#define GLFW_EXPOSE_NATIVE_GLX
+#include "glfw/include/GLFW/glfw3.h"
+#include "glfw/include/GLFW/glfw3native.h"
+*/
+import "C"
+import "unsafe"
+
+// GetX11Display returns the X11 Display* used by GLFW.
+//
+// Possible errors include NotInitialized.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (c *Context) GetX11Display() (unsafe.Pointer, error) {
+	var display unsafe.Pointer
+	err := catchError(func() { display = unsafe.Pointer(C.glfwGetX11Display()) })
+	if display == nil {
+		return nil, nativeError(err, "X11")
+	}
+	return display, nil
+}
+
+// GetX11Window returns the X11 Window (XID) of win.
+//
+// Possible errors include NotInitialized.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (win *Window) GetX11Window() (uintptr, error) {
+	var window uintptr
+	err := catchError(func() { window = uintptr(C.glfwGetX11Window(win.c())) })
+	if window == 0 {
+		return 0, nativeError(err, "X11")
+	}
+	return window, nil
+}
+
+// GetX11Adapter returns the RandR CRTC (RRCrtc) of monitor.
+//
+// Possible errors include NotInitialized.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (monitor *Monitor) GetX11Adapter() (uintptr, error) {
+	var crtc uintptr
+	err := catchError(func() { crtc = uintptr(C.glfwGetX11Adapter(monitor.c())) })
+	if crtc == 0 {
+		return 0, nativeError(err, "X11")
+	}
+	return crtc, nil
+}
+
+// GetX11Monitor returns the RandR output (RROutput) of monitor.
+//
+// Possible errors include NotInitialized.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (monitor *Monitor) GetX11Monitor() (uintptr, error) {
+	var output uintptr
+	err := catchError(func() { output = uintptr(C.glfwGetX11Monitor(monitor.c())) })
+	if output == 0 {
+		return 0, nativeError(err, "X11")
+	}
+	return output, nil
+}
+
+// GetGLXContext returns the GLXContext of win.
+//
+// Possible errors include NotInitialized and NoWindowContext.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (win *Window) GetGLXContext() (unsafe.Pointer, error) {
+	var context unsafe.Pointer
+	err := catchError(func() { context = unsafe.Pointer(C.glfwGetGLXContext(win.c())) })
+	if context == nil {
+		return nil, nativeError(err, "GLX")
+	}
+	return context, nil
+}
+
+// GetGLXWindow returns the GLXWindow (XID) of win.
+//
+// Possible errors include NotInitialized and NoWindowContext.
+//
+// This function may be called from any thread. Access is not synchronized.
+func (win *Window) GetGLXWindow() (uintptr, error) {
+	var window uintptr
+	err := catchError(func() { window = uintptr(C.glfwGetGLXWindow(win.c())) })
+	if window == 0 {
+		return 0, nativeError(err, "GLX")
+	}
+	return window, nil
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build !linux wayland glfw_null
+
+package glfw
+
+import "unsafe"
+
+// GetX11Display returns the X11 Display* used by GLFW.
+//
+// GLFW is not built with the X11 backend, so this function always returns a
+// NativeAPIError.
+func (c *Context) GetX11Display() (unsafe.Pointer, error) {
+	return nil, &NativeAPIError{API: "X11"}
+}
+
+// GetX11Window returns the X11 Window (XID) of win.
+//
+// GLFW is not built with the X11 backend, so this function always returns a
+// NativeAPIError.
+func (win *Window) GetX11Window() (uintptr, error) {
+	return 0, &NativeAPIError{API: "X11"}
+}
+
+// GetX11Adapter returns the RandR CRTC (RRCrtc) of monitor.
+//
+// GLFW is not built with the X11 backend, so this function always returns a
+// NativeAPIError.
+func (monitor *Monitor) GetX11Adapter() (uintptr, error) {
+	return 0, &NativeAPIError{API: "X11"}
+}
+
+// GetX11Monitor returns the RandR output (RROutput) of monitor.
+//
+// GLFW is not built with the X11 backend, so this function always returns a
+// NativeAPIError.
+func (monitor *Monitor) GetX11Monitor() (uintptr, error) {
+	return 0, &NativeAPIError{API: "X11"}
+}
+
+// GetGLXContext returns the GLXContext of win.
+//
+// GLFW is not built with the X11 backend, so this function always returns a
+// NativeAPIError.
+func (win *Window) GetGLXContext() (unsafe.Pointer, error) {
+	return nil, &NativeAPIError{API: "GLX"}
+}
+
+// GetGLXWindow returns the GLXWindow (XID) of win.
+//
+// GLFW is not built with the X11 backend, so this function always returns a
+// NativeAPIError.
+func (win *Window) GetGLXWindow() (uintptr, error) {
+	return 0, &NativeAPIError{API: "GLX"}
+}
@@ -0,0 +1,604 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package glfw
+
+/*
+#include "glfw/include/GLFW/glfw3.h"
+*/
+import "C"
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// RecordingVersion is the version of the recording file format written by
+// Recorder.
+//
+// A recording starts with a header of the 8-byte magic "GLFWREC\x00", the
+// version as a little-endian uint16 and the timer frequency of the recording
+// as a little-endian uint64. The header is followed by records, each made of a
+// record kind byte, the timer value at which the record was made as a uint64,
+// and a payload depending on the kind.
+const RecordingVersion = 1
+
+const recordingMagic = "GLFWREC\x00"
+
+// Record kinds of the recording file format.
+const (
+	recordFrame           = 1
+	recordGamepadState    = 2
+	recordJoystick        = 3
+	recordWindowPos       = 16
+	recordWindowSize      = 17
+	recordWindowClose     = 18
+	recordWindowRefresh   = 19
+	recordFocus           = 20
+	recordIconify         = 21
+	recordMaximize        = 22
+	recordFramebufferSize = 23
+	recordContentScale    = 24
+	recordKey             = 25
+	recordChar            = 26
+	recordCharMods        = 27
+	recordMouseButton     = 28
+	recordCursorPos       = 29
+	recordCursorEnter     = 30
+	recordScroll          = 31
+	recordDrop            = 32
+)
+
+// Limits of the drop records read by Replayer, beyond which a recording is
+// considered corrupt.
+const (
+	maxRecordedDropPaths  = 1 << 16
+	maxRecordedPathLength = 1 << 16
+)
+
+// ReplayMode specifies the timing of a replay.
+type ReplayMode int
+
+const (
+	// ReplayRealtime : Replay the events with their original timing.
+	ReplayRealtime ReplayMode = iota
+	// ReplayImmediate : Replay the events as fast as possible.
+	ReplayImmediate
+)
+
+// Recorder records the events dispatched by the binding to a recording, which
+// can be replayed with a Replayer.
+//
+// All window events and joystick connection events are recorded, along with
+// the end of each call to Context.PollEvents(), Context.WaitEvents() or
+// Context.WaitEventsTimeout(), so that the events can be replayed frame by
+// frame. At the end of each call, the gamepad states of the joysticks that
+// changed since the previous call are recorded, so that the gamepad states
+// returned by Joystick.GetGamepadState() while replaying a frame are those of
+// the recorded frame. Monitor events are not recorded.
+type Recorder struct {
+	w       *bufio.Writer
+	windows map[*Window]uint32
+	err     error
+
+	// gamepads are the gamepad states last recorded, valid once framed is
+	// true.
+	gamepads [JoystickLast + 1]recordedGamepadState
+	framed   bool
+}
+
+// recorder is the active recorder, or nil if not recording.
+var recorder *Recorder
+
+// StartRecording starts recording events to w.
+//
+// The windows are identified in the recording by their index in windows, which
+// must be passed in the same order to Context.StartReplay(). Windows that are
+// not in windows are numbered after them, in the order their first event is
+// recorded.
+//
+// Only one recording may be active at a time.
+//
+// Possible errors include NotInitialized.
+//
+// This function must only be called from the main thread.
+func (c *Context) StartRecording(w io.Writer, windows ...*Window) (*Recorder, error) {
+	if recorder != nil {
+		return nil, errors.New("glfw: a recording is already active")
+	}
+
+	r := &Recorder{
+		w:       bufio.NewWriter(w),
+		windows: make(map[*Window]uint32, len(windows)),
+	}
+	for i, win := range windows {
+		r.windows[win] = uint32(i)
+	}
+	r.w.WriteString(recordingMagic)
+	r.write(uint16(RecordingVersion), uint64(C.glfwGetTimerFrequency()))
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	enableEvents()
+	recorder = r
+	return r, nil
+}
+
+// Stop stops the recording and flushes it to the underlying writer. Returns the
+// first error that occurred while writing the recording.
+//
+// This function must only be called from the main thread.
+func (r *Recorder) Stop() error {
+	if recorder == r {
+		recorder = nil
+	}
+	if r.err == nil {
+		r.err = r.w.Flush()
+	}
+	return r.err
+}
+
+// write writes the fixed-size values vs, unless an error occurred before.
+func (r *Recorder) write(vs ...interface{}) {
+	for _, v := range vs {
+		if r.err != nil {
+			return
+		}
+		r.err = binary.Write(r.w, binary.LittleEndian, v)
+	}
+}
+
+// begin writes the header of a record of kind.
+func (r *Recorder) begin(kind uint8) {
+	r.write(kind, uint64(C.glfwGetTimerValue()))
+}
+
+// window returns the identifier of win in the recording.
+func (r *Recorder) window(win *Window) uint32 {
+	id, exist := r.windows[win]
+	if !exist {
+		id = uint32(len(r.windows))
+		r.windows[win] = id
+	}
+	return id
+}
+
+// endFrame records the gamepad states that changed during a call to an event
+// processing function, and the end of the call. The states of all joysticks
+// are recorded at the end of the first call.
+func (r *Recorder) endFrame() {
+	for j := Joystick1; j <= JoystickLast; j++ {
+		var recorded recordedGamepadState
+		if state, ok := j.GetGamepadState(); ok {
+			recorded = recordedGamepadState{state: *state, ok: true}
+		}
+		if r.framed && recorded == r.gamepads[j] {
+			continue
+		}
+		r.gamepads[j] = recorded
+		r.recordGamepadState(j, recorded)
+	}
+	r.framed = true
+	r.begin(recordFrame)
+}
+
+// recordGamepadState records the gamepad state of j.
+func (r *Recorder) recordGamepadState(j Joystick, recorded recordedGamepadState) {
+	var buttons [15]uint8
+	for i, button := range recorded.state.Buttons {
+		buttons[i] = uint8(button)
+	}
+	r.begin(recordGamepadState)
+	r.write(int32(j), boolByte(recorded.ok), buttons, recorded.state.Axes)
+}
+
+// record records event.
+func (r *Recorder) record(event Event) {
+	switch e := event.(type) {
+	case WindowPosEvent:
+		r.begin(recordWindowPos)
+		r.write(r.window(e.Window), int32(e.X), int32(e.Y))
+	case WindowSizeEvent:
+		r.begin(recordWindowSize)
+		r.write(r.window(e.Window), int32(e.Width), int32(e.Height))
+	case WindowCloseEvent:
+		r.begin(recordWindowClose)
+		r.write(r.window(e.Window))
+	case WindowRefreshEvent:
+		r.begin(recordWindowRefresh)
+		r.write(r.window(e.Window))
+	case FocusEvent:
+		r.begin(recordFocus)
+		r.write(r.window(e.Window), boolByte(e.Focused))
+	case IconifyEvent:
+		r.begin(recordIconify)
+		r.write(r.window(e.Window), boolByte(e.Iconified))
+	case MaximizeEvent:
+		r.begin(recordMaximize)
+		r.write(r.window(e.Window), boolByte(e.Maximized))
+	case FramebufferSizeEvent:
+		r.begin(recordFramebufferSize)
+		r.write(r.window(e.Window), int32(e.Width), int32(e.Height))
+	case ContentScaleEvent:
+		r.begin(recordContentScale)
+		r.write(r.window(e.Window), e.XScale, e.YScale)
+	case KeyEvent:
+		r.begin(recordKey)
+		r.write(r.window(e.Window), int32(e.Key), int32(e.Scancode), int32(e.Action), int32(e.Mods))
+	case CharEvent:
+		r.begin(recordChar)
+		r.write(r.window(e.Window), int32(e.Codepoint))
+	case CharModsEvent:
+		r.begin(recordCharMods)
+		r.write(r.window(e.Window), int32(e.Codepoint), int32(e.Mods))
+	case MouseButtonEvent:
+		r.begin(recordMouseButton)
+		r.write(r.window(e.Window), int32(e.Button), int32(e.Action), int32(e.Mods))
+	case CursorPosEvent:
+		r.begin(recordCursorPos)
+		r.write(r.window(e.Window), e.X, e.Y)
+	case CursorEnterEvent:
+		r.begin(recordCursorEnter)
+		r.write(r.window(e.Window), boolByte(e.Entered))
+	case ScrollEvent:
+		r.begin(recordScroll)
+		r.write(r.window(e.Window), e.XOffset, e.YOffset)
+	case DropEvent:
+		r.begin(recordDrop)
+		r.write(r.window(e.Window), uint32(len(e.Paths)))
+		for _, path := range e.Paths {
+			r.write(uint32(len(path)))
+			if r.err == nil {
+				_, r.err = r.w.WriteString(path)
+			}
+		}
+	case JoystickEvent:
+		r.begin(recordJoystick)
+		r.write(int32(e.Joystick), int32(e.Event))
+	case GamepadButtonEvent, GamepadAxisEvent:
+		// Not recorded, as they are synthesized again from the recorded
+		// gamepad states when replaying.
+	}
+}
+
+// Replayer replays a recording made by a Recorder.
+//
+// Replayer.PollEvents() is called in place of Context.PollEvents() to feed the
+// recorded events of each frame to the callbacks and the event queue. While
+// replaying, Joystick.GetGamepadState() returns the recorded gamepad states
+// instead of the actual ones, for the joysticks present in the recording.
+//
+// The replayed events are not recorded by an active Recorder, so that
+// recording while replaying records only the events processed by
+// Context.PollEvents() and the other event processing functions.
+type Replayer struct {
+	r         *bufio.Reader
+	mode      ReplayMode
+	windows   []*Window
+	frequency uint64
+
+	started   bool
+	startTime time.Time
+	firstTime uint64
+
+	gamepads map[Joystick]recordedGamepadState
+}
+
+// recordedGamepadState is a gamepad state read from a recording.
+type recordedGamepadState struct {
+	state GamepadState
+	ok    bool
+}
+
+// replayer is the active replayer, or nil if not replaying.
+var replayer *Replayer
+
+// replaying reports whether Replayer.PollEvents() is dispatching recorded
+// events, which must not be recorded again by an active recorder.
+var replaying bool
+
+// StartReplay starts replaying the recording read from r with the timing of
+// mode.
+//
+// The events of the window identified by i in the recording are dispatched to
+// windows[i]. Events of windows not in windows are discarded.
+//
+// Only one replay may be active at a time.
+//
+// This function must only be called from the main thread.
+func (c *Context) StartReplay(r io.Reader, mode ReplayMode, windows ...*Window) (*Replayer, error) {
+	if replayer != nil {
+		return nil, errors.New("glfw: a replay is already active")
+	}
+
+	p := &Replayer{
+		r:        bufio.NewReader(r),
+		mode:     mode,
+		windows:  windows,
+		gamepads: make(map[Joystick]recordedGamepadState),
+	}
+	var magic [len(recordingMagic)]byte
+	var version uint16
+	if err := p.read(&magic, &version, &p.frequency); err != nil {
+		return nil, fmt.Errorf("glfw: reading recording header: %v", err)
+	}
+	if string(magic[:]) != recordingMagic {
+		return nil, errors.New("glfw: not a recording")
+	}
+	if version != RecordingVersion {
+		return nil, fmt.Errorf("glfw: unsupported recording version %d", version)
+	}
+	if p.frequency == 0 {
+		return nil, errors.New("glfw: invalid timer frequency in recording")
+	}
+
+	replayer = p
+	return p, nil
+}
+
+// Stop stops the replay. Joystick.GetGamepadState() returns the actual gamepad
+// states again.
+//
+// This function must only be called from the main thread.
+func (p *Replayer) Stop() {
+	if replayer == p {
+		replayer = nil
+	}
+}
+
+// PollEvents dispatches the recorded events of the next frame, i.e. the events
+// that were processed by one call to an event processing function while
+// recording. In ReplayRealtime mode, it waits until each event is due relative
+// to the first call.
+//
+// Returns io.EOF when the end of the recording is reached.
+//
+// This function must not be called from a callback.
+//
+// This function must only be called from the main thread.
+func (p *Replayer) PollEvents() error {
+	replaying = true
+	defer func() {
+		replaying = false
+	}()
+	for {
+		var kind uint8
+		var timestamp uint64
+		if err := p.read(&kind, &timestamp); err != nil {
+			return err
+		}
+		p.wait(timestamp)
+
+		if kind == recordFrame {
+			if gamepadEventsEnabled() {
+				pollGamepadEvents()
+				raiseCallbackPanic()
+			}
+			return nil
+		}
+		if err := p.dispatch(kind); err != nil {
+			return err
+		}
+	}
+}
+
+// read reads the fixed-size values vs. Returns io.ErrUnexpectedEOF if the
+// recording ends in the middle of the values.
+func (p *Replayer) read(vs ...interface{}) error {
+	for i, v := range vs {
+		if err := binary.Read(p.r, binary.LittleEndian, v); err != nil {
+			if i > 0 && err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// wait waits until a record made at timestamp is due, in ReplayRealtime mode.
+func (p *Replayer) wait(timestamp uint64) {
+	if !p.started {
+		p.started = true
+		p.startTime = time.Now()
+		p.firstTime = timestamp
+	}
+	if p.mode != ReplayRealtime || timestamp < p.firstTime {
+		return
+	}
+
+	ticks := timestamp - p.firstTime
+	due := time.Duration(ticks/p.frequency)*time.Second +
+		time.Duration(ticks%p.frequency)*time.Second/time.Duration(p.frequency)
+	if d := due - time.Since(p.startTime); d > 0 {
+		time.Sleep(d)
+	}
+}
+
+// readPath reads a path of a drop record. The path is read as it arrives, so
+// that a corrupt length cannot allocate more than what is left in the
+// recording.
+func (p *Replayer) readPath() (string, error) {
+	var length uint32
+	if err := p.read(&length); err != nil {
+		return "", err
+	}
+	if length > maxRecordedPathLength {
+		return "", fmt.Errorf("glfw: path too long in drop record: %d bytes", length)
+	}
+	var path strings.Builder
+	if n, err := io.CopyN(&path, p.r, int64(length)); err != nil {
+		if err == io.EOF && n < int64(length) {
+			err = io.ErrUnexpectedEOF
+		}
+		return "", err
+	}
+	return path.String(), nil
+}
+
+// window reads a window identifier and returns the window it refers to, or nil
+// if there is no such window.
+func (p *Replayer) window() (*Window, error) {
+	var id uint32
+	if err := p.read(&id); err != nil {
+		return nil, err
+	}
+	if int(id) >= len(p.windows) {
+		return nil, nil
+	}
+	return p.windows[id], nil
+}
+
+// dispatch reads the payload of a record of kind and dispatches it.
+func (p *Replayer) dispatch(kind uint8) (err error) {
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+
+	switch kind {
+	case recordGamepadState:
+		var jid int32
+		var ok uint8
+		var buttons [15]uint8
+		var state recordedGamepadState
+		if err := p.read(&jid, &ok, &buttons, &state.state.Axes); err != nil {
+			return err
+		}
+		for i, button := range buttons {
+			state.state.Buttons[i] = Action(button)
+		}
+		state.ok = ok != 0
+		p.gamepads[Joystick(jid)] = state
+		return nil
+	case recordJoystick:
+		var jid, event int32
+		if err := p.read(&jid, &event); err != nil {
+			return err
+		}
+		dispatchEvent(JoystickEvent{Joystick: Joystick(jid), Event: ConnectionEvent(event)})
+		return nil
+	}
+
+	win, err := p.window()
+	if err != nil {
+		return err
+	}
+	var event Event
+	switch kind {
+	case recordWindowPos, recordWindowSize, recordFramebufferSize:
+		var a, b int32
+		err = p.read(&a, &b)
+		switch kind {
+		case recordWindowPos:
+			event = WindowPosEvent{Window: win, X: int(a), Y: int(b)}
+		case recordWindowSize:
+			event = WindowSizeEvent{Window: win, Width: int(a), Height: int(b)}
+		default:
+			event = FramebufferSizeEvent{Window: win, Width: int(a), Height: int(b)}
+		}
+	case recordWindowClose:
+		event = WindowCloseEvent{Window: win}
+	case recordWindowRefresh:
+		event = WindowRefreshEvent{Window: win}
+	case recordFocus, recordIconify, recordMaximize, recordCursorEnter:
+		var b uint8
+		err = p.read(&b)
+		switch kind {
+		case recordFocus:
+			event = FocusEvent{Window: win, Focused: b != 0}
+		case recordIconify:
+			event = IconifyEvent{Window: win, Iconified: b != 0}
+		case recordMaximize:
+			event = MaximizeEvent{Window: win, Maximized: b != 0}
+		default:
+			event = CursorEnterEvent{Window: win, Entered: b != 0}
+		}
+	case recordContentScale:
+		var x, y float32
+		err = p.read(&x, &y)
+		event = ContentScaleEvent{Window: win, XScale: x, YScale: y}
+	case recordKey:
+		var key, scancode, action, mods int32
+		err = p.read(&key, &scancode, &action, &mods)
+		event = KeyEvent{Window: win, Key: Key(key), Scancode: int(scancode), Action: Action(action), Mods: ModifierFlag(mods)}
+	case recordChar:
+		var codepoint int32
+		err = p.read(&codepoint)
+		event = CharEvent{Window: win, Codepoint: rune(codepoint)}
+	case recordCharMods:
+		var codepoint, mods int32
+		err = p.read(&codepoint, &mods)
+		event = CharModsEvent{Window: win, Codepoint: rune(codepoint), Mods: ModifierFlag(mods)}
+	case recordMouseButton:
+		var button, action, mods int32
+		err = p.read(&button, &action, &mods)
+		event = MouseButtonEvent{Window: win, Button: Button(button), Action: Action(action), Mods: ModifierFlag(mods)}
+	case recordCursorPos, recordScroll:
+		var x, y float64
+		err = p.read(&x, &y)
+		if kind == recordCursorPos {
+			event = CursorPosEvent{Window: win, X: x, Y: y}
+		} else {
+			event = ScrollEvent{Window: win, XOffset: x, YOffset: y}
+		}
+	case recordDrop:
+		var count uint32
+		if err = p.read(&count); err != nil {
+			return err
+		}
+		if count > maxRecordedDropPaths {
+			return fmt.Errorf("glfw: too many paths in drop record: %d", count)
+		}
+		var paths []string
+		for i := uint32(0); i < count && err == nil; i++ {
+			var path string
+			if path, err = p.readPath(); err == nil {
+				paths = append(paths, path)
+			}
+		}
+		event = DropEvent{Window: win, Paths: paths}
+	default:
+		return fmt.Errorf("glfw: unknown record kind %d", kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	if win != nil {
+		dispatchEvent(event)
+	}
+	return nil
+}
+
+// replayedGamepadState returns the gamepad state of j at the current point of
+// the active replay. found is false if there is no active replay or no state of
+// j has been replayed.
+func replayedGamepadState(j Joystick) (state *GamepadState, ok, found bool) {
+	if replayer == nil {
+		return nil, false, false
+	}
+	recorded, found := replayer.gamepads[j]
+	if !found || !recorded.ok {
+		return nil, false, found
+	}
+	state = new(GamepadState)
+	*state = recorded.state
+	return state, true, true
+}
+
+// boolByte returns 1 if b is true, or 0 otherwise.
+func boolByte(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package glfw
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+// recording returns a recording with the records made of the fixed-size
+// values vs.
+func recording(vs ...interface{}) *bytes.Buffer {
+	var buf bytes.Buffer
+	buf.WriteString(recordingMagic)
+	binary.Write(&buf, binary.LittleEndian, uint16(RecordingVersion))
+	binary.Write(&buf, binary.LittleEndian, uint64(1000000))
+	for _, v := range vs {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	return &buf
+}
+
+func TestReplayCorruptDrop(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload []interface{}
+		eof     bool
+	}{
+		{"TooManyPaths", []interface{}{uint32(0xFFFFFFFF)}, false},
+		{"PathTooLong", []interface{}{uint32(1), uint32(0xFFFFFFFF)}, false},
+		{"TruncatedPath", []interface{}{uint32(1), uint32(1000), []byte("abc")}, true},
+		{"MissingPaths", []interface{}{uint32(1000)}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			records := append([]interface{}{uint8(recordDrop), uint64(0), uint32(0)}, test.payload...)
+			p, err := new(Context).StartReplay(recording(records...), ReplayImmediate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer p.Stop()
+
+			err = p.PollEvents()
+			if err == nil || err == io.EOF {
+				t.Fatalf("PollEvents() = %v, want an error", err)
+			}
+			if (err == io.ErrUnexpectedEOF) != test.eof {
+				t.Errorf("PollEvents() = %v", err)
+			}
+		})
+	}
+}
+
+func TestReplayGamepadState(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+
+	var buf bytes.Buffer
+	r, err := ctx.StartRecording(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Joystick1.InjectConnect("pad", testGUID)
+	for frame := 1; frame <= 3; frame++ {
+		var state GamepadState
+		state.Axes[GamepadAxisLeftX] = float32(frame) / 4
+		Joystick1.InjectGamepadState(&state)
+		ctx.PollEvents()
+	}
+	if err := r.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	Joystick1.InjectDisconnect()
+
+	p, err := ctx.StartReplay(&buf, ReplayImmediate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Stop()
+	for frame := 1; frame <= 3; frame++ {
+		if err := p.PollEvents(); err != nil {
+			t.Fatal(err)
+		}
+		state, ok := Joystick1.GetGamepadState()
+		if want := float32(frame) / 4; !ok || state.Axes[GamepadAxisLeftX] != want {
+			t.Errorf("frame %d: GetGamepadState() = %v, %v, want left X %v", frame, state, ok, want)
+		}
+		if _, ok := Joystick2.GetGamepadState(); ok {
+			t.Errorf("frame %d: GetGamepadState() of an absent joystick = true", frame)
+		}
+	}
+	if err := p.PollEvents(); err != io.EOF {
+		t.Errorf("PollEvents() at the end = %v, want io.EOF", err)
+	}
+}
+
+func TestRecordWhileReplaying(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	defer win.Destroy()
+
+	replayed := recording(uint8(recordKey), uint64(0), uint32(0), int32(KeyA), int32(0), int32(Press), int32(0),
+		uint8(recordFrame), uint64(0))
+	p, err := ctx.StartReplay(replayed, ReplayImmediate, win)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Stop()
+	var buf bytes.Buffer
+	r, err := ctx.StartRecording(&buf, win)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []Key
+	win.SetKeyCallback(func(win *Window, key Key, scancode int, action Action, mods ModifierFlag) {
+		keys = append(keys, key)
+	})
+
+	if err := p.PollEvents(); err != nil {
+		t.Fatal(err)
+	}
+	win.InjectKey(KeyB, 0, Press, 0)
+	ctx.PollEvents()
+	if err := r.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("key callback called with %v, want [A B]", keys)
+	}
+
+	keys = nil
+	p.Stop()
+	p, err = ctx.StartReplay(&buf, ReplayImmediate, win)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if err := p.PollEvents(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(keys) != 1 || keys[0] != KeyB {
+		t.Errorf("recording made while replaying has keys %v, want [B]", keys)
+	}
+}
@@ -0,0 +1,445 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package glfw
+
+/*
+#include "glfw/include/GLFW/glfw3.h"
+
+void _errorCallback(int, char*);
+
+// The description is only read by _errorCallback.
+static void _errorListenerCallback(int err, const char* desc) {
+	_errorCallback(err, (char*)desc);
+}
+
+static void goSetErrorListenerCallback() {
+	glfwSetErrorCallback(_errorListenerCallback);
+}
+*/
+import "C"
+import "sort"
+
+// Subscription is a listener subscribed to an event with one of the On
+// methods, e.g. Window.OnKey().
+//
+// Unlike the callbacks set with the Set methods, e.g. Window.SetKeyCallback(),
+// any number of listeners can be subscribed to the same event. They are
+// notified from the highest priority to the lowest, and in the order they were
+// subscribed for equal priorities. A listener returns true to stop the
+// propagation of the event to the next listeners and to the callback set for
+// the event, which is called after all the listeners otherwise. The events are
+// queued to the event queue either way.
+type Subscription struct {
+	list     *listenerList
+	listener func(Event) bool
+	priority int
+}
+
+// Unsubscribe unsubscribes the listener. It is not notified anymore, even of
+// the event being delivered if called from a listener.
+//
+// This function must only be called from the main thread.
+func (s *Subscription) Unsubscribe() {
+	if s.list != nil {
+		s.list.remove(s)
+		s.list = nil
+	}
+}
+
+// SetPriority sets the priority of the listener, 0 by default, and returns s.
+// Listeners with a higher priority are notified first. The listener is moved
+// after the other listeners of the same priority.
+//
+// This function must only be called from the main thread.
+func (s *Subscription) SetPriority(priority int) *Subscription {
+	if list := s.list; list != nil {
+		list.remove(s)
+		s.priority = priority
+		list.add(s)
+	} else {
+		s.priority = priority
+	}
+	return s
+}
+
+// Priority returns the priority of the listener.
+func (s *Subscription) Priority() int {
+	return s.priority
+}
+
+// listenerList is a list of subscriptions sorted by priority. The slice of
+// subscriptions is copied on write, so that listeners can subscribe and
+// unsubscribe while it is being notified.
+type listenerList struct {
+	subscriptions []*Subscription
+}
+
+// subscribe subscribes listener to l. listener is only passed the events of
+// the type l is the list of.
+func (l *listenerList) subscribe(listener func(Event) bool) *Subscription {
+	s := &Subscription{list: l, listener: listener}
+	l.add(s)
+	return s
+}
+
+// add inserts s after the subscriptions of higher or equal priority.
+func (l *listenerList) add(s *Subscription) {
+	i := sort.Search(len(l.subscriptions), func(i int) bool {
+		return l.subscriptions[i].priority < s.priority
+	})
+	subscriptions := make([]*Subscription, 0, len(l.subscriptions)+1)
+	subscriptions = append(subscriptions, l.subscriptions[:i]...)
+	subscriptions = append(subscriptions, s)
+	l.subscriptions = append(subscriptions, l.subscriptions[i:]...)
+}
+
+// remove removes s.
+func (l *listenerList) remove(s *Subscription) {
+	for i, subscription := range l.subscriptions {
+		if subscription == s {
+			subscriptions := make([]*Subscription, 0, len(l.subscriptions)-1)
+			subscriptions = append(subscriptions, l.subscriptions[:i]...)
+			l.subscriptions = append(subscriptions, l.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+// empty reports whether no listener is subscribed to l.
+func (l *listenerList) empty() bool {
+	return len(l.subscriptions) == 0
+}
+
+// notify notifies the listeners of event until one of them returns true, and
+// returns whether one did. The listeners unsubscribed by a previous listener
+// are skipped.
+func (l *listenerList) notify(event Event) bool {
+	for _, s := range l.subscriptions {
+		if s.list == l && s.listener(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// windowListeners are the listeners subscribed to the events of a window.
+type windowListeners struct {
+	pos             listenerList
+	size            listenerList
+	close           listenerList
+	refresh         listenerList
+	focus           listenerList
+	iconify         listenerList
+	maximize        listenerList
+	framebufferSize listenerList
+	contentScale    listenerList
+	key             listenerList
+	char            listenerList
+	charMods        listenerList
+	mouseButton     listenerList
+	cursorPos       listenerList
+	cursorEnter     listenerList
+	scroll          listenerList
+	drop            listenerList
+}
+
+// Listeners of the events that are not window events.
+var (
+	errorListeners         listenerList
+	monitorListeners       listenerList
+	joystickListeners      listenerList
+	gamepadButtonListeners listenerList
+	gamepadAxisListeners   listenerList
+
+	// eventListeners is whether a listener was subscribed to window, monitor
+	// or joystick events since the library was initialized, which keeps the
+	// GLFW callbacks set.
+	eventListeners bool
+)
+
+// subscribeWindow subscribes listener to the events of win of the type of
+// event.
+func subscribeWindow(win *Window, event Event, listener func(Event) bool) *Subscription {
+	state := win.state()
+	if state == nil {
+		// The window was destroyed, the listener will never be notified.
+		return new(listenerList).subscribe(listener)
+	}
+	enableEvents()
+	eventListeners = true
+	return listenersOf(state, event).subscribe(listener)
+}
+
+// OnPos subscribes listener to the WindowPosEvent events of win, delivered when
+// the window is moved. See Window.SetPosCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnPos(listener func(WindowPosEvent) bool) *Subscription {
+	return subscribeWindow(win, WindowPosEvent{}, func(e Event) bool { return listener(e.(WindowPosEvent)) })
+}
+
+// OnSize subscribes listener to the WindowSizeEvent events of win, delivered
+// when the window is resized. See Window.SetSizeCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnSize(listener func(WindowSizeEvent) bool) *Subscription {
+	return subscribeWindow(win, WindowSizeEvent{}, func(e Event) bool { return listener(e.(WindowSizeEvent)) })
+}
+
+// OnClose subscribes listener to the WindowCloseEvent events of win, delivered
+// when the user attempts to close the window. See Window.SetCloseCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnClose(listener func(WindowCloseEvent) bool) *Subscription {
+	return subscribeWindow(win, WindowCloseEvent{}, func(e Event) bool { return listener(e.(WindowCloseEvent)) })
+}
+
+// OnRefresh subscribes listener to the WindowRefreshEvent events of win,
+// delivered when the content area of the window needs to be redrawn. See
+// Window.SetRefreshCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnRefresh(listener func(WindowRefreshEvent) bool) *Subscription {
+	return subscribeWindow(win, WindowRefreshEvent{}, func(e Event) bool { return listener(e.(WindowRefreshEvent)) })
+}
+
+// OnFocus subscribes listener to the FocusEvent events of win, delivered when
+// the window gains or loses input focus. See Window.SetFocusCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnFocus(listener func(FocusEvent) bool) *Subscription {
+	return subscribeWindow(win, FocusEvent{}, func(e Event) bool { return listener(e.(FocusEvent)) })
+}
+
+// OnIconify subscribes listener to the IconifyEvent events of win, delivered
+// when the window is iconified or restored. See Window.SetIconifyCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnIconify(listener func(IconifyEvent) bool) *Subscription {
+	return subscribeWindow(win, IconifyEvent{}, func(e Event) bool { return listener(e.(IconifyEvent)) })
+}
+
+// OnMaximize subscribes listener to the MaximizeEvent events of win, delivered
+// when the window is maximized or restored. See Window.SetMaximizeCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnMaximize(listener func(MaximizeEvent) bool) *Subscription {
+	return subscribeWindow(win, MaximizeEvent{}, func(e Event) bool { return listener(e.(MaximizeEvent)) })
+}
+
+// OnFramebufferSize subscribes listener to the FramebufferSizeEvent events of
+// win, delivered when the framebuffer of the window is resized. See
+// Window.SetFramebufferSizeCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnFramebufferSize(listener func(FramebufferSizeEvent) bool) *Subscription {
+	return subscribeWindow(win, FramebufferSizeEvent{}, func(e Event) bool { return listener(e.(FramebufferSizeEvent)) })
+}
+
+// OnContentScale subscribes listener to the ContentScaleEvent events of win,
+// delivered when the content scale of the window changes. See
+// Window.SetWindowContentScaleCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnContentScale(listener func(ContentScaleEvent) bool) *Subscription {
+	return subscribeWindow(win, ContentScaleEvent{}, func(e Event) bool { return listener(e.(ContentScaleEvent)) })
+}
+
+// OnKey subscribes listener to the KeyEvent events of win, delivered when a key
+// is pressed, repeated or released. See Window.SetKeyCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnKey(listener func(KeyEvent) bool) *Subscription {
+	return subscribeWindow(win, KeyEvent{}, func(e Event) bool { return listener(e.(KeyEvent)) })
+}
+
+// OnChar subscribes listener to the CharEvent events of win, delivered when a
+// Unicode character is input. See Window.SetCharCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnChar(listener func(CharEvent) bool) *Subscription {
+	return subscribeWindow(win, CharEvent{}, func(e Event) bool { return listener(e.(CharEvent)) })
+}
+
+// OnCharMods subscribes listener to the CharModsEvent events of win, delivered
+// when a Unicode character is input, with the modifier keys held down. See
+// Window.SetCharModsCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnCharMods(listener func(CharModsEvent) bool) *Subscription {
+	return subscribeWindow(win, CharModsEvent{}, func(e Event) bool { return listener(e.(CharModsEvent)) })
+}
+
+// OnMouseButton subscribes listener to the MouseButtonEvent events of win,
+// delivered when a mouse button is pressed or released. See
+// Window.SetMouseButtonCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnMouseButton(listener func(MouseButtonEvent) bool) *Subscription {
+	return subscribeWindow(win, MouseButtonEvent{}, func(e Event) bool { return listener(e.(MouseButtonEvent)) })
+}
+
+// OnCursorPos subscribes listener to the CursorPosEvent events of win,
+// delivered when the cursor moves. See Window.SetCursorPosCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnCursorPos(listener func(CursorPosEvent) bool) *Subscription {
+	return subscribeWindow(win, CursorPosEvent{}, func(e Event) bool { return listener(e.(CursorPosEvent)) })
+}
+
+// OnCursorEnter subscribes listener to the CursorEnterEvent events of win,
+// delivered when the cursor enters or leaves the content area of the window.
+// See Window.SetCursorEnterCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnCursorEnter(listener func(CursorEnterEvent) bool) *Subscription {
+	return subscribeWindow(win, CursorEnterEvent{}, func(e Event) bool { return listener(e.(CursorEnterEvent)) })
+}
+
+// OnScroll subscribes listener to the ScrollEvent events of win, delivered when
+// the user scrolls. See Window.SetScrollCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnScroll(listener func(ScrollEvent) bool) *Subscription {
+	return subscribeWindow(win, ScrollEvent{}, func(e Event) bool { return listener(e.(ScrollEvent)) })
+}
+
+// OnDrop subscribes listener to the DropEvent events of win, delivered when
+// paths are dropped on the window. See Window.SetDropCallback().
+//
+// This function must only be called from the main thread.
+func (win *Window) OnDrop(listener func(DropEvent) bool) *Subscription {
+	return subscribeWindow(win, DropEvent{}, func(e Event) bool { return listener(e.(DropEvent)) })
+}
+
+// OnMonitor subscribes listener to the MonitorEvent events, delivered when a
+// monitor is connected or disconnected. See Context.SetMonitorCallback().
+//
+// This function must only be called from the main thread.
+func (c *Context) OnMonitor(listener func(MonitorEvent) bool) *Subscription {
+	enableEvents()
+	eventListeners = true
+	return monitorListeners.subscribe(func(e Event) bool { return listener(e.(MonitorEvent)) })
+}
+
+// OnJoystick subscribes listener to the JoystickEvent events, delivered when a
+// joystick is connected or disconnected. See Context.SetJoystickCallback().
+//
+// This function must only be called from the main thread.
+func (c *Context) OnJoystick(listener func(JoystickEvent) bool) *Subscription {
+	enableEvents()
+	eventListeners = true
+	return joystickListeners.subscribe(func(e Event) bool { return listener(e.(JoystickEvent)) })
+}
+
+// OnGamepadButton subscribes listener to the GamepadButtonEvent events,
+// delivered when a gamepad button is pressed or released. See
+// Context.SetGamepadButtonCallback().
+//
+// This function must only be called from the main thread.
+func (c *Context) OnGamepadButton(listener func(GamepadButtonEvent) bool) *Subscription {
+	return gamepadButtonListeners.subscribe(func(e Event) bool { return listener(e.(GamepadButtonEvent)) })
+}
+
+// OnGamepadAxis subscribes listener to the GamepadAxisEvent events, delivered
+// when a gamepad axis moves. See Context.SetGamepadAxisCallback().
+//
+// This function must only be called from the main thread.
+func (c *Context) OnGamepadAxis(listener func(GamepadAxisEvent) bool) *Subscription {
+	return gamepadAxisListeners.subscribe(func(e Event) bool { return listener(e.(GamepadAxisEvent)) })
+}
+
+// OnError subscribes listener to the errors, reported with their error code
+// and description. See SetErrorCallback(). Like the error callback, the
+// listeners remain subscribed after the library has been terminated.
+//
+// This function must only be called from the main thread.
+func OnError(listener func(err Error, desc string) bool) *Subscription {
+	if errorCallback == nil && errorListeners.empty() {
+		C.goSetErrorListenerCallback()
+	}
+	return errorListeners.subscribe(func(e Event) bool {
+		err := e.(errorEvent)
+		return listener(err.code, err.desc)
+	})
+}
+
+// errorEvent is an error notified to the error listeners. It is never queued.
+type errorEvent struct {
+	code Error
+	desc string
+}
+
+func (errorEvent) isEvent() {}
+
+// notifyListeners notifies the listeners subscribed to event, state being the
+// state of its window if it is a window event, and returns whether a listener
+// stopped the propagation of event.
+func notifyListeners(state *windowState, event Event) bool {
+	if list := listenersOf(state, event); list != nil {
+		return list.notify(event)
+	}
+	return false
+}
+
+// listenersOf returns the list of the listeners subscribed to the events of
+// the type of event, state being the state of its window if it is a window
+// event, or nil if there is no such list.
+func listenersOf(state *windowState, event Event) *listenerList {
+	switch event.(type) {
+	case MonitorEvent:
+		return &monitorListeners
+	case JoystickEvent:
+		return &joystickListeners
+	case GamepadButtonEvent:
+		return &gamepadButtonListeners
+	case GamepadAxisEvent:
+		return &gamepadAxisListeners
+	case errorEvent:
+		return &errorListeners
+	}
+	if state == nil {
+		return nil
+	}
+	l := &state.listeners
+	switch event.(type) {
+	case WindowPosEvent:
+		return &l.pos
+	case WindowSizeEvent:
+		return &l.size
+	case WindowCloseEvent:
+		return &l.close
+	case WindowRefreshEvent:
+		return &l.refresh
+	case FocusEvent:
+		return &l.focus
+	case IconifyEvent:
+		return &l.iconify
+	case MaximizeEvent:
+		return &l.maximize
+	case FramebufferSizeEvent:
+		return &l.framebufferSize
+	case ContentScaleEvent:
+		return &l.contentScale
+	case KeyEvent:
+		return &l.key
+	case CharEvent:
+		return &l.char
+	case CharModsEvent:
+		return &l.charMods
+	case MouseButtonEvent:
+		return &l.mouseButton
+	case CursorPosEvent:
+		return &l.cursorPos
+	case CursorEnterEvent:
+		return &l.cursorEnter
+	case ScrollEvent:
+		return &l.scroll
+	case DropEvent:
+		return &l.drop
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package glfw
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubscriptionPriority(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	defer win.Destroy()
+
+	var calls []string
+	listen := func(name string) func(KeyEvent) bool {
+		return func(KeyEvent) bool {
+			calls = append(calls, name)
+			return false
+		}
+	}
+	win.OnKey(listen("a"))
+	win.OnKey(listen("high")).SetPriority(10)
+	win.OnKey(listen("b"))
+	low := win.OnKey(listen("low")).SetPriority(-1)
+	win.OnKey(listen("c")).SetPriority(10)
+	win.SetKeyCallback(func(win *Window, key Key, scancode int, action Action, mods ModifierFlag) {
+		calls = append(calls, "callback")
+	})
+
+	win.InjectKey(KeyA, 0, Press, 0)
+	if want := []string{"high", "c", "a", "b", "low", "callback"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("notified in the order %v, want %v", calls, want)
+	}
+
+	// A listener whose priority is set again moves after the listeners of its
+	// new priority.
+	calls = nil
+	low.SetPriority(10)
+	win.InjectKey(KeyA, 0, Release, 0)
+	if want := []string{"high", "c", "low", "a", "b", "callback"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("notified in the order %v, want %v", calls, want)
+	}
+	if low.Priority() != 10 {
+		t.Errorf("Priority() = %d, want 10", low.Priority())
+	}
+}
+
+func TestSubscriptionStopPropagation(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	defer win.Destroy()
+	queue := ctx.Events()
+
+	var calls []string
+	win.OnScroll(func(e ScrollEvent) bool {
+		calls = append(calls, "first")
+		return e.YOffset > 0
+	})
+	win.OnScroll(func(ScrollEvent) bool {
+		calls = append(calls, "second")
+		return false
+	})
+	win.SetScrollCallback(func(win *Window, xoff, yoff float64) {
+		calls = append(calls, "callback")
+	})
+
+	win.InjectScroll(0, 1)
+	if want := []string{"first"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("event stopped by a listener delivered to %v, want %v", calls, want)
+	}
+	calls = nil
+	win.InjectScroll(0, -1)
+	if want := []string{"first", "second", "callback"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("event not stopped delivered to %v, want %v", calls, want)
+	}
+	if events := queue.Drain(); len(events) != 2 {
+		t.Errorf("queued events = %v, want both scroll events", events)
+	}
+}
+
+func TestUnsubscribeDuringDispatch(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	defer win.Destroy()
+
+	var calls []string
+	var self, next, added *Subscription
+	self = win.OnChar(func(CharEvent) bool {
+		calls = append(calls, "self")
+		self.Unsubscribe()
+		next.Unsubscribe()
+		added = win.OnChar(func(CharEvent) bool {
+			calls = append(calls, "added")
+			return false
+		})
+		return false
+	})
+	next = win.OnChar(func(CharEvent) bool {
+		calls = append(calls, "next")
+		return false
+	})
+	win.OnChar(func(CharEvent) bool {
+		calls = append(calls, "last")
+		return false
+	})
+
+	// The listeners unsubscribed during the dispatch are not notified of the
+	// event being delivered, and those subscribed are only notified of the
+	// next events.
+	win.InjectChar('a', 0)
+	if want := []string{"self", "last"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("notified %v, want %v", calls, want)
+	}
+	calls = nil
+	win.InjectChar('b', 0)
+	if want := []string{"last", "added"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("notified %v, want %v", calls, want)
+	}
+
+	added.Unsubscribe()
+	added.Unsubscribe()
+	added.SetPriority(1)
+	calls = nil
+	win.InjectChar('c', 0)
+	if want := []string{"last"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("notified %v after unsubscribing, want %v", calls, want)
+	}
+}
+
+func TestSubscriptionGlobal(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+
+	var joysticks []JoystickEvent
+	sub := ctx.OnJoystick(func(e JoystickEvent) bool {
+		joysticks = append(joysticks, e)
+		return true
+	})
+	called := false
+	ctx.SetJoystickCallback(func(j Joystick, event ConnectionEvent) {
+		called = true
+	})
+	Joystick3.InjectConnect("Test Pad", testGUID)
+	sub.Unsubscribe()
+	Joystick3.InjectDisconnect()
+	if want := []JoystickEvent{{Joystick: Joystick3, Event: Connected}}; !reflect.DeepEqual(joysticks, want) || !called {
+		t.Errorf("joystick listener notified of %v, callback called %v", joysticks, called)
+	}
+
+	var errs []Error
+	errSub := OnError(func(err Error, desc string) bool {
+		errs = append(errs, err)
+		return false
+	})
+	defer errSub.Unsubscribe()
+	inputError(InvalidValue, "test")
+	if want := []Error{InvalidValue}; !reflect.DeepEqual(errs, want) {
+		t.Errorf("error listener notified of %v, want %v", errs, want)
+	}
+}
+
+func TestSubscribeDestroyedWindow(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	win.Destroy()
+
+	sub := win.OnKey(func(KeyEvent) bool { return false })
+	sub.SetPriority(1)
+	sub.Unsubscribe()
+}
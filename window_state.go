@@ -0,0 +1,149 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package glfw
+
+/*
+#include <stdint.h>
+#include "glfw/include/GLFW/glfw3.h"
+
+static void goSetWindowHandle(GLFWwindow* window, uintptr_t handle) {
+	glfwSetWindowUserPointer(window, (void*)handle);
+}
+
+static uintptr_t goGetWindowHandle(GLFWwindow* window) {
+	return (uintptr_t)glfwGetWindowUserPointer(window);
+}
+*/
+import "C"
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// windowState is the Go state of a window.
+//
+// Each window created by Context.CreateWindow() is given a handle, an index in
+// the windowStates table, stored as its GLFW user pointer. The C callbacks
+// pass the handle of the window to the Go callbacks, which find the state of
+// the window with a single index. The user pointer of the application is kept
+// in the state instead.
+type windowState struct {
+	win         *Window
+	callbacks   WindowCallbacks
+	listeners   windowListeners
+	userPointer unsafe.Pointer
+}
+
+// windowStates is the table of window states indexed by handle, holding a
+// []*windowState. Handle 0 is never used, so that a window with no handle has
+// no state. The table is copied on write, as windows are created and
+// destroyed, so that it can be read without locking.
+var windowStates atomic.Value
+
+// freeWindowHandles are the handles of destroyed windows, reused by new
+// windows. Unlike windowStates, it is not safe for concurrent use: windows are
+// only created and destroyed on the main thread.
+var freeWindowHandles []uintptr
+
+// loadWindowStates returns the table of window states.
+func loadWindowStates() []*windowState {
+	states, _ := windowStates.Load().([]*windowState)
+	return states
+}
+
+// newWindowState creates the state of win, and stores its handle as the user
+// pointer of win.
+func newWindowState(win *Window) *windowState {
+	states := loadWindowStates()
+	var handle uintptr
+	if n := len(freeWindowHandles); n > 0 {
+		handle = freeWindowHandles[n-1]
+		freeWindowHandles = freeWindowHandles[:n-1]
+	} else if handle = uintptr(len(states)); handle == 0 {
+		handle = 1
+	}
+
+	size := len(states)
+	if int(handle) >= size {
+		size = int(handle) + 1
+	}
+	updated := make([]*windowState, size)
+	copy(updated, states)
+	state := &windowState{win: win}
+	updated[handle] = state
+	windowStates.Store(updated)
+
+	C.goSetWindowHandle(win.c(), C.uintptr_t(handle))
+	return state
+}
+
+// deleteWindowState deletes the state of win and closes its input states,
+// before win is destroyed.
+func deleteWindowState(win *Window) {
+	closeInputStates(win)
+	handle := uintptr(C.goGetWindowHandle(win.c()))
+	states := loadWindowStates()
+	if handle == 0 || handle >= uintptr(len(states)) || states[handle] == nil {
+		return
+	}
+	updated := make([]*windowState, len(states))
+	copy(updated, states)
+	updated[handle] = nil
+	windowStates.Store(updated)
+	freeWindowHandles = append(freeWindowHandles, handle)
+}
+
+// resetWindowStates deletes the states of all windows, on termination.
+func resetWindowStates() {
+	windowStates.Store([]*windowState(nil))
+	freeWindowHandles = nil
+}
+
+// windowStateOf returns the state of the window with handle, or nil if there is
+// no such window.
+func windowStateOf(handle C.uintptr_t) *windowState {
+	states := loadWindowStates()
+	if uintptr(handle) >= uintptr(len(states)) {
+		return nil
+	}
+	return states[handle]
+}
+
+// state returns the state of win, or nil if win was not created by
+// Context.CreateWindow() or was destroyed.
+func (win *Window) state() *windowState {
+	if win == nil {
+		return nil
+	}
+	return windowStateOf(C.goGetWindowHandle(win.c()))
+}
+
+// noWindowCallbacks are the callbacks of the windows with no state.
+var noWindowCallbacks WindowCallbacks
+
+// directCallbacks returns the callbacks set for the window with handle if the
+// events of its input callbacks, which are frequent, can be delivered straight
+// to them with no Event allocated. This is the case unless events are queued,
+// recorded, tracked by input states or listened to, in which case nil is
+// returned and the events must be dispatched.
+func directCallbacks(handle C.uintptr_t) *WindowCallbacks {
+	if eventsEnabled() {
+		return nil
+	}
+	if state := windowStateOf(handle); state != nil {
+		return &state.callbacks
+	}
+	return &noWindowCallbacks
+}
+
+// callbacks returns the callbacks set for win. The callbacks of a window with
+// no state are discarded.
+func (win *Window) callbacks() *WindowCallbacks {
+	if state := win.state(); state != nil {
+		return &state.callbacks
+	}
+	return new(WindowCallbacks)
+}
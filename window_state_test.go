@@ -0,0 +1,196 @@
+// Copyright (c) 2018 Beta Kuang
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// +build glfw_null
+
+package glfw
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+// windowHandle returns the handle of win in the windowStates table.
+func windowHandle(win *Window) uintptr {
+	for handle, state := range loadWindowStates() {
+		if state != nil && state.win == win {
+			return uintptr(handle)
+		}
+	}
+	return 0
+}
+
+// hasCallbacks reports whether any callback of win is set.
+func hasCallbacks(win *Window) bool {
+	callbacks := reflect.ValueOf(win.callbacks()).Elem()
+	for i := 0; i < callbacks.NumField(); i++ {
+		if !callbacks.Field(i).IsNil() {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDestroyClearsCallbacks(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+
+	win := createTestWindow(t, ctx)
+	stale := false
+	win.SetKeyCallback(func(win *Window, key Key, scancode int, action Action, mods ModifierFlag) {
+		stale = true
+	})
+	win.SetCursorPosCallback(func(win *Window, x, y float64) {
+		stale = true
+	})
+	var data int
+	win.SetUserPointer(unsafe.Pointer(&data))
+	handle := windowHandle(win)
+	if handle == 0 {
+		t.Fatal("window has no handle")
+	}
+	win.Destroy()
+	if states := loadWindowStates(); states[handle] != nil {
+		t.Fatal("destroyed window still has a state")
+	}
+
+	reused := createTestWindow(t, ctx)
+	defer reused.Destroy()
+	if windowHandle(reused) != handle {
+		t.Fatalf("new window has handle %d, want the freed handle %d", windowHandle(reused), handle)
+	}
+	if hasCallbacks(reused) {
+		t.Error("new window inherited the callbacks of the destroyed window")
+	}
+	if reused.GetUserPointer() != nil {
+		t.Error("new window inherited the user pointer of the destroyed window")
+	}
+	reused.InjectKey(KeyA, 0, Press, 0)
+	reused.InjectCursorPos(10, 20)
+	if stale {
+		t.Error("callback of the destroyed window called for the new window")
+	}
+}
+
+func TestTerminateClearsState(t *testing.T) {
+	ctx := initTest(t)
+	win := createTestWindow(t, ctx)
+	win.SetKeyCallback(func(win *Window, key Key, scancode int, action Action, mods ModifierFlag) {})
+	destroyed := createTestWindow(t, ctx)
+	destroyed.Destroy()
+	ctx.SetMonitorCallback(func(monitor *Monitor, event ConnectionEvent) {})
+	ctx.SetJoystickCallback(func(j Joystick, event ConnectionEvent) {})
+	ctx.Terminate()
+
+	if states := loadWindowStates(); len(states) != 0 {
+		t.Errorf("window states after Terminate() = %v, want none", states)
+	}
+	if len(freeWindowHandles) != 0 {
+		t.Errorf("free window handles after Terminate() = %v, want none", freeWindowHandles)
+	}
+	if monitorCallback != nil || joystickCallback != nil {
+		t.Error("monitor or joystick callback still set after Terminate()")
+	}
+
+	ctx = initTest(t)
+	defer ctx.Terminate()
+	win = createTestWindow(t, ctx)
+	if windowHandle(win) != 1 {
+		t.Errorf("first window after Terminate() has handle %d, want 1", windowHandle(win))
+	}
+	if hasCallbacks(win) {
+		t.Error("first window after Terminate() has callbacks")
+	}
+}
+
+func TestWindowHandleReuse(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+
+	windows := []*Window{createTestWindow(t, ctx), createTestWindow(t, ctx), createTestWindow(t, ctx)}
+	destroyed := windows[1]
+	handle := windowHandle(destroyed)
+	destroyed.OnKey(func(KeyEvent) bool { return false })
+	destroyed.SetCharCallback(func(win *Window, char rune) {})
+	destroyed.Destroy()
+
+	win := createTestWindow(t, ctx)
+	if windowHandle(win) != handle {
+		t.Fatalf("new window has handle %d, want the freed handle %d", windowHandle(win), handle)
+	}
+	state := win.state()
+	if state == nil || state.win != win {
+		t.Fatal("new window does not own the state of its handle")
+	}
+	if hasCallbacks(win) || !state.listeners.key.empty() {
+		t.Error("new window inherited the callbacks or listeners of the destroyed window")
+	}
+	for _, other := range []*Window{windows[0], windows[2]} {
+		if other.state() == nil || other.state().win != other {
+			t.Error("state of another window changed by the reuse of a handle")
+		}
+	}
+
+	next := createTestWindow(t, ctx)
+	if windowHandle(next) != 4 {
+		t.Errorf("window created with no free handle has handle %d, want 4", windowHandle(next))
+	}
+}
+
+func TestDirectDispatch(t *testing.T) {
+	ctx := initTest(t)
+	defer ctx.Terminate()
+	win := createTestWindow(t, ctx)
+	defer win.Destroy()
+	var x float64
+	win.SetCursorPosCallback(func(win *Window, xpos, ypos float64) {
+		x = xpos
+	})
+
+	// With no queue, recorder, input state or listener, the events of the
+	// input callbacks allocate nothing.
+	i := 0
+	allocs := testing.AllocsPerRun(100, func() {
+		i++
+		win.InjectCursorPos(float64(i), 0)
+	})
+	if allocs != 0 || x != float64(i) {
+		t.Errorf("direct dispatch made %v allocations and delivered %v, want none and %d", allocs, x, i)
+	}
+
+	queue := ctx.Events()
+	win.InjectCursorPos(-1, 0)
+	if events := queue.Drain(); x != -1 || len(events) != 1 {
+		t.Errorf("dispatch with a queue delivered %v and queued %v", x, events)
+	}
+	queue.Close()
+	win.InjectCursorPos(-2, 0)
+	if x != -2 {
+		t.Errorf("direct dispatch after the queue was closed delivered %v", x)
+	}
+}
+
+// BenchmarkCursorPosDispatch measures the dispatch of a cursor position event
+// to its callback, as for each report of a 1000 Hz mouse.
+func BenchmarkCursorPosDispatch(b *testing.B) {
+	ctx := initTest(b)
+	defer ctx.Terminate()
+	win := createTestWindow(b, ctx)
+	var x, y float64
+	win.SetCursorPosCallback(func(win *Window, xpos, ypos float64) {
+		x, y = xpos, ypos
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		win.InjectCursorPos(float64(i), float64(i&0xFF))
+	}
+	b.StopTimer()
+	if x != float64(b.N-1) {
+		b.Fatalf("last cursor position %v, %v, want %v", x, y, b.N-1)
+	}
+}